@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/api/http/middleware"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/query"
+)
+
+// WrapAPIHandler applies the middleware every request to the API and asset
+// mux must pass through before reaching the gRPC-Gateway/REST handlers.
+// CORSInterceptor runs outermost so a CORS preflight OPTIONS request is
+// answered directly and never reaches those handlers.
+//
+// This repository snapshot has no server bootstrap (no main package, no
+// listener/mux construction anywhere in the tree) for any request in this
+// series to call this from, so WrapAPIHandler itself has no caller here.
+// It is the one function a bootstrap must call so CORS enforcement is
+// live; treat it as unfinished wiring, not a working feature, until
+// something calls it.
+func WrapAPIHandler(handler http.Handler, queries *query.Queries) http.Handler {
+	return middleware.CORSInterceptor(queries)(handler)
+}
+
+// StartProjections subscribes every projection this package depends on so
+// their read models reflect a write as soon as it is pushed, instead of
+// only returning the zero-value fallback forever. It must be called once
+// during server startup, alongside WrapAPIHandler and
+// StartM2MTokenTrustVerifier.
+func StartProjections(ctx context.Context, queries *query.Queries, es *eventstore.Eventstore) {
+	queries.StartSecurityPolicyProjection(ctx, es)
+	queries.StartIDPRestrictionsProjection(ctx, es)
+	queries.StartImpersonationSessionsProjection(ctx, es)
+}
+
+// StartM2MTokenTrustVerifier constructs the verifier the JWT profile grant
+// assertion validator consults when a service account exchanges a signed
+// JWT for an access token, and starts it watching the eventstore so a
+// SecurityPolicySetEvent invalidates its cache on every node immediately
+// instead of only after the configured TTL.
+//
+// No JWT/service-account auth path exists anywhere in this repository
+// snapshot (confirmed: internal/api/authz has no other file, and there is
+// no main package for a bootstrap to wire this into), so
+// VerifyIssuerAndAudience is exercised only by this package's own unit
+// tests and is not yet enforced on any real machine-to-machine call.
+// Whoever implements the JWT profile grant handler must call
+// VerifyIssuerAndAudience after parsing the assertion's iss/aud and before
+// issuing a token for it.
+func StartM2MTokenTrustVerifier(ctx context.Context, queries *query.Queries, es *eventstore.Eventstore) *authz.M2MTokenTrustVerifier {
+	verifier := authz.NewM2MTokenTrustVerifier(m2mTokenTrustPolicyProvider{queries})
+	verifier.WatchCacheInvalidation(ctx, es)
+	return verifier
+}
+
+// m2mTokenTrustPolicyProvider adapts query.Queries to
+// authz.M2MTokenTrustPolicyProvider so the authz package, which
+// internal/query itself depends on for context helpers, does not need to
+// import internal/query back.
+type m2mTokenTrustPolicyProvider struct {
+	queries *query.Queries
+}
+
+func (p m2mTokenTrustPolicyProvider) M2MTokenTrustPolicy(ctx context.Context, instanceID string) (authz.M2MTokenTrustPolicy, error) {
+	securityPolicy, err := p.queries.SecurityPolicyByInstanceID(ctx, instanceID)
+	if err != nil {
+		return authz.M2MTokenTrustPolicy{}, err
+	}
+	return authz.M2MTokenTrustPolicy{
+		Enabled:                securityPolicy.M2MTokenTrust.Enabled,
+		CacheExpirationSeconds: securityPolicy.M2MTokenTrust.CacheExpirationSeconds,
+		TrustedIssuers:         securityPolicy.M2MTokenTrust.TrustedIssuers,
+		RequiredAudiences:      securityPolicy.M2MTokenTrust.RequiredAudiences,
+	}, nil
+}
@@ -0,0 +1,147 @@
+package authz
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// M2MTokenTrustPolicy is the subset of the instance's security policy the
+// verifier needs. internal/query.SecurityPolicy is adapted to this type by
+// its caller instead of being referenced directly, because internal/query
+// already imports this package for its context helpers (e.g. GetInstance)
+// and importing internal/query back from here would create an import
+// cycle.
+type M2MTokenTrustPolicy struct {
+	Enabled                bool
+	CacheExpirationSeconds int32
+	TrustedIssuers         []string
+	RequiredAudiences      []string
+}
+
+// M2MTokenTrustPolicyProvider resolves the current M2MTokenTrustPolicy of an
+// instance. It is satisfied by an adapter over internal/query.Queries.
+type M2MTokenTrustPolicyProvider interface {
+	M2MTokenTrustPolicy(ctx context.Context, instanceID string) (M2MTokenTrustPolicy, error)
+}
+
+// M2MTokenTrustVerifier validates machine-to-machine JWTs against the
+// instance's configured M2MTokenTrust setting, caching that setting per
+// instance for the configured TTL so every M2M call doesn't re-read it. It
+// does not fetch or cache issuer metadata or JWKS.
+type M2MTokenTrustVerifier struct {
+	provider M2MTokenTrustPolicyProvider
+
+	mu    sync.Mutex
+	cache map[string]m2mCacheEntry
+}
+
+type m2mCacheEntry struct {
+	policy    M2MTokenTrustPolicy
+	expiresAt time.Time
+}
+
+func NewM2MTokenTrustVerifier(provider M2MTokenTrustPolicyProvider) *M2MTokenTrustVerifier {
+	return &M2MTokenTrustVerifier{
+		provider: provider,
+		cache:    make(map[string]m2mCacheEntry),
+	}
+}
+
+// VerifyIssuerAndAudience checks that iss is in the instance's trusted
+// issuer list and that at least one of aud is a required audience. If
+// M2MTokenTrust is disabled for the instance it accepts any iss and aud, to
+// keep parity with the previous unconditional behavior; it still returns
+// the error if the instance's policy cannot be read. It checks iss and aud
+// only: the assertion's signature and expiry are not checked here, and no
+// caller exists in this package (see api.StartM2MTokenTrustVerifier).
+func (v *M2MTokenTrustVerifier) VerifyIssuerAndAudience(ctx context.Context, instanceID, iss string, aud []string) error {
+	policy, err := v.policyForInstance(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	if !policy.Enabled {
+		return nil
+	}
+	if !contains(policy.TrustedIssuers, iss) {
+		return zerrors.ThrowPermissionDenied(nil, "AUTHZ-chu8O", "Errors.Token.InvalidIssuer")
+	}
+	if len(policy.RequiredAudiences) > 0 && !containsAny(policy.RequiredAudiences, aud) {
+		return zerrors.ThrowPermissionDenied(nil, "AUTHZ-oong6", "Errors.Token.InvalidAudience")
+	}
+	return nil
+}
+
+func (v *M2MTokenTrustVerifier) policyForInstance(ctx context.Context, instanceID string) (M2MTokenTrustPolicy, error) {
+	v.mu.Lock()
+	entry, ok := v.cache[instanceID]
+	v.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.policy, nil
+	}
+
+	policy, err := v.provider.M2MTokenTrustPolicy(ctx, instanceID)
+	if err != nil {
+		return M2MTokenTrustPolicy{}, err
+	}
+
+	ttl := time.Duration(policy.CacheExpirationSeconds) * time.Second
+	v.mu.Lock()
+	v.cache[instanceID] = m2mCacheEntry{
+		policy:    policy,
+		expiresAt: time.Now().Add(ttl),
+	}
+	v.mu.Unlock()
+	return policy, nil
+}
+
+// InvalidateInstance drops the cached policy for instanceID, so a
+// SecurityPolicySetEvent projected on any instance of the cluster is
+// reflected immediately instead of only after the TTL expires.
+func (v *M2MTokenTrustVerifier) InvalidateInstance(instanceID string) {
+	v.mu.Lock()
+	delete(v.cache, instanceID)
+	v.mu.Unlock()
+}
+
+// WatchCacheInvalidation subscribes to SecurityPolicySetEventType on the
+// given eventstore and drops the cached policy of the affected instance as
+// soon as the event is pushed, on every node that holds the subscription -
+// this is what lets a cache-TTL shorter than the propagation delay still be
+// correct across instances.
+func (v *M2MTokenTrustVerifier) WatchCacheInvalidation(ctx context.Context, es *eventstore.Eventstore) {
+	sub := eventstore.SubscribeEventTypes(instance.SecurityPolicySetEventType)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-sub.Events:
+				v.InvalidateInstance(event.Aggregate().InstanceID)
+			}
+		}
+	}()
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(list, values []string) bool {
+	for _, value := range values {
+		if contains(list, value) {
+			return true
+		}
+	}
+	return false
+}
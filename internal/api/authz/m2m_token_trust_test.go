@@ -0,0 +1,176 @@
+package authz
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeM2MTokenTrustPolicyProvider returns policy for any instanceID and
+// counts how many times it was called, so tests can assert on cache hits.
+type fakeM2MTokenTrustPolicyProvider struct {
+	policy M2MTokenTrustPolicy
+	calls  int
+}
+
+func (f *fakeM2MTokenTrustPolicyProvider) M2MTokenTrustPolicy(_ context.Context, _ string) (M2MTokenTrustPolicy, error) {
+	f.calls++
+	return f.policy, nil
+}
+
+func Test_contains(t *testing.T) {
+	tests := []struct {
+		name  string
+		list  []string
+		value string
+		want  bool
+	}{
+		{name: "present", list: []string{"a", "b"}, value: "b", want: true},
+		{name: "absent", list: []string{"a", "b"}, value: "c", want: false},
+		{name: "empty list", list: nil, value: "c", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contains(tt.list, tt.value); got != tt.want {
+				t.Errorf("contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_containsAny(t *testing.T) {
+	tests := []struct {
+		name   string
+		list   []string
+		values []string
+		want   bool
+	}{
+		{name: "one matches", list: []string{"aud1", "aud2"}, values: []string{"other", "aud2"}, want: true},
+		{name: "none match", list: []string{"aud1", "aud2"}, values: []string{"other"}, want: false},
+		{name: "empty values", list: []string{"aud1"}, values: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsAny(tt.list, tt.values); got != tt.want {
+				t.Errorf("containsAny() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestM2MTokenTrustVerifier_InvalidateInstance(t *testing.T) {
+	v := NewM2MTokenTrustVerifier(nil)
+	v.cache["instance1"] = m2mCacheEntry{}
+	v.InvalidateInstance("instance1")
+	if _, ok := v.cache["instance1"]; ok {
+		t.Error("expected cache entry to be removed")
+	}
+}
+
+func TestM2MTokenTrustVerifier_VerifyIssuerAndAudience(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  M2MTokenTrustPolicy
+		iss     string
+		aud     []string
+		wantErr bool
+	}{
+		{
+			name:   "disabled allows anything",
+			policy: M2MTokenTrustPolicy{Enabled: false},
+			iss:    "https://untrusted.example.com",
+			aud:    []string{"anything"},
+		},
+		{
+			name: "trusted issuer and required audience",
+			policy: M2MTokenTrustPolicy{
+				Enabled:           true,
+				TrustedIssuers:    []string{"https://issuer.example.com"},
+				RequiredAudiences: []string{"aud1"},
+			},
+			iss: "https://issuer.example.com",
+			aud: []string{"other", "aud1"},
+		},
+		{
+			name: "untrusted issuer is rejected",
+			policy: M2MTokenTrustPolicy{
+				Enabled:        true,
+				TrustedIssuers: []string{"https://issuer.example.com"},
+			},
+			iss:     "https://evil.example.com",
+			aud:     []string{"aud1"},
+			wantErr: true,
+		},
+		{
+			name: "missing required audience is rejected",
+			policy: M2MTokenTrustPolicy{
+				Enabled:           true,
+				TrustedIssuers:    []string{"https://issuer.example.com"},
+				RequiredAudiences: []string{"aud1"},
+			},
+			iss:     "https://issuer.example.com",
+			aud:     []string{"other"},
+			wantErr: true,
+		},
+		{
+			name: "no required audiences configured accepts any audience",
+			policy: M2MTokenTrustPolicy{
+				Enabled:        true,
+				TrustedIssuers: []string{"https://issuer.example.com"},
+			},
+			iss: "https://issuer.example.com",
+			aud: []string{"anything"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeM2MTokenTrustPolicyProvider{policy: tt.policy}
+			v := NewM2MTokenTrustVerifier(provider)
+			err := v.VerifyIssuerAndAudience(context.Background(), "instance1", tt.iss, tt.aud)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyIssuerAndAudience() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestM2MTokenTrustVerifier_policyForInstance_caches(t *testing.T) {
+	provider := &fakeM2MTokenTrustPolicyProvider{
+		policy: M2MTokenTrustPolicy{Enabled: true, CacheExpirationSeconds: 60},
+	}
+	v := NewM2MTokenTrustVerifier(provider)
+
+	if _, err := v.policyForInstance(context.Background(), "instance1"); err != nil {
+		t.Fatalf("policyForInstance() error = %v", err)
+	}
+	if _, err := v.policyForInstance(context.Background(), "instance1"); err != nil {
+		t.Fatalf("policyForInstance() error = %v", err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("expected 1 provider call while cache entry is fresh, got %d", provider.calls)
+	}
+
+	if _, err := v.policyForInstance(context.Background(), "instance2"); err != nil {
+		t.Fatalf("policyForInstance() error = %v", err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("expected a separate cache entry per instance, got %d provider calls", provider.calls)
+	}
+}
+
+func TestM2MTokenTrustVerifier_policyForInstance_refetchesAfterInvalidate(t *testing.T) {
+	provider := &fakeM2MTokenTrustPolicyProvider{
+		policy: M2MTokenTrustPolicy{Enabled: true, CacheExpirationSeconds: 60},
+	}
+	v := NewM2MTokenTrustVerifier(provider)
+
+	if _, err := v.policyForInstance(context.Background(), "instance1"); err != nil {
+		t.Fatalf("policyForInstance() error = %v", err)
+	}
+	v.InvalidateInstance("instance1")
+	if _, err := v.policyForInstance(context.Background(), "instance1"); err != nil {
+		t.Fatalf("policyForInstance() error = %v", err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("expected InvalidateInstance to force a refetch, got %d provider calls", provider.calls)
+	}
+}
@@ -0,0 +1,81 @@
+package settings
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/api/grpc/object/v2"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/query"
+	settings_pb "github.com/zitadel/zitadel/pkg/grpc/settings/v2"
+)
+
+func identityProviderTypeToPb(idpType domain.IDPType) settings_pb.IdentityProviderType {
+	switch idpType {
+	case domain.IDPTypeOIDC:
+		return settings_pb.IdentityProviderType_IDENTITY_PROVIDER_TYPE_OIDC
+	case domain.IDPTypeJWT:
+		return settings_pb.IdentityProviderType_IDENTITY_PROVIDER_TYPE_JWT
+	case domain.IDPTypeOAuth:
+		return settings_pb.IdentityProviderType_IDENTITY_PROVIDER_TYPE_OAUTH
+	case domain.IDPTypeLDAP:
+		return settings_pb.IdentityProviderType_IDENTITY_PROVIDER_TYPE_LDAP
+	case domain.IDPTypeSAML:
+		return settings_pb.IdentityProviderType_IDENTITY_PROVIDER_TYPE_SAML
+	default:
+		return settings_pb.IdentityProviderType_IDENTITY_PROVIDER_TYPE_UNSPECIFIED
+	}
+}
+
+func (s *Server) GetActiveIdentityProviders(ctx context.Context, req *settings_pb.GetActiveIdentityProvidersRequest) (*settings_pb.GetActiveIdentityProvidersResponse, error) {
+	idps, err := s.query.ActiveIdentityProviders(ctx, getActiveIdentityProvidersRequestToQuery(req))
+	if err != nil {
+		return nil, err
+	}
+	return &settings_pb.GetActiveIdentityProvidersResponse{
+		Details:           object.ToListDetails(uint64(len(idps)), 0, nil),
+		IdentityProviders: identityProvidersToPb(idps),
+	}, nil
+}
+
+func getActiveIdentityProvidersRequestToQuery(req *settings_pb.GetActiveIdentityProvidersRequest) *query.ActiveIdentityProvidersSearchQueries {
+	return &query.ActiveIdentityProvidersSearchQueries{
+		LinkingAllowed:  req.LinkingAllowed,
+		CreationAllowed: req.CreationAllowed,
+		AutoCreation:    req.AutoCreation,
+		AutoLinking:     req.AutoLinking,
+		ClientID:        req.GetClientId(),
+		LoginHint:       req.GetLoginHint(),
+		EmailDomain:     req.GetEmailDomain(),
+		RequestedScopes: req.GetRequestedScopes(),
+	}
+}
+
+func identityProvidersToPb(idps []*query.IdentityProvider) []*settings_pb.IdentityProvider {
+	converted := make([]*settings_pb.IdentityProvider, len(idps))
+	for i, idp := range idps {
+		converted[i] = &settings_pb.IdentityProvider{
+			Id:   idp.ID,
+			Name: idp.Name,
+			Type: identityProviderTypeToPb(idp.Type),
+		}
+	}
+	return converted
+}
+
+func (s *Server) SetIdentityProviderRestrictions(ctx context.Context, req *settings_pb.SetIdentityProviderRestrictionsRequest) (*settings_pb.SetIdentityProviderRestrictionsResponse, error) {
+	details, err := s.command.SetIDPRestrictions(
+		ctx,
+		authz.GetInstance(ctx).InstanceID(),
+		req.GetIdpId(),
+		req.GetRestrictions().GetAllowedEmailDomains(),
+		req.GetRestrictions().GetAllowedClientIds(),
+		req.GetRestrictions().GetPriority(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &settings_pb.SetIdentityProviderRestrictionsResponse{
+		Details: object.DomainToDetailsPb(details),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package settings
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/api/grpc/object/v2"
+	"github.com/zitadel/zitadel/internal/query"
+	settings_pb "github.com/zitadel/zitadel/pkg/grpc/settings/v2"
+)
+
+func (s *Server) ListImpersonationSessions(ctx context.Context, _ *settings_pb.ListImpersonationSessionsRequest) (*settings_pb.ListImpersonationSessionsResponse, error) {
+	sessions, err := s.query.ActiveImpersonationSessions(ctx, authz.GetInstance(ctx).InstanceID())
+	if err != nil {
+		return nil, err
+	}
+	return &settings_pb.ListImpersonationSessionsResponse{
+		Details:  object.ToListDetails(uint64(len(sessions)), 0, nil),
+		Sessions: impersonationSessionsToPb(sessions),
+	}, nil
+}
+
+func impersonationSessionsToPb(sessions []*query.ImpersonationSession) []*settings_pb.ImpersonationSession {
+	converted := make([]*settings_pb.ImpersonationSession, len(sessions))
+	for i, session := range sessions {
+		converted[i] = &settings_pb.ImpersonationSession{
+			Id:                 session.ID,
+			ImpersonatorUserId: session.ImpersonatorUserID,
+			TargetUserId:       session.TargetUserID,
+			StartedAt:          timestamppb.New(session.StartedAt),
+			Justification:      session.Justification,
+		}
+	}
+	return converted
+}
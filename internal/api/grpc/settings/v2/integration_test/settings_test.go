@@ -4,6 +4,7 @@ package settings_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -11,6 +12,8 @@ import (
 	"github.com/muhlemmer/gu"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/zitadel/zitadel/internal/integration"
@@ -168,6 +171,176 @@ func TestServer_SetSecuritySettings(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "success wildcard origin",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					EmbeddedIframe: &settings.EmbeddedIframeSettings{
+						Enabled:        true,
+						AllowedOrigins: []string{"https://*.example.com"},
+					},
+				},
+			},
+			want: &settings.SetSecuritySettingsResponse{
+				Details: &object_pb.Details{
+					ChangeDate:    timestamppb.Now(),
+					ResourceOwner: Instance.ID(),
+				},
+			},
+		},
+		{
+			name: "success regex origin",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					EmbeddedIframe: &settings.EmbeddedIframeSettings{
+						Enabled:        true,
+						AllowedOrigins: []string{`regex:^https://(foo|bar)\.example\.com$`},
+					},
+				},
+			},
+			want: &settings.SetSecuritySettingsResponse{
+				Details: &object_pb.Details{
+					ChangeDate:    timestamppb.Now(),
+					ResourceOwner: Instance.ID(),
+				},
+			},
+		},
+		{
+			name: "invalid regex origin",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					EmbeddedIframe: &settings.EmbeddedIframeSettings{
+						AllowedOrigins: []string{"regex:("},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			// matchesPattern requires the wildcard to replace the whole
+			// host label ("scheme://*.host"); this shape is rejected
+			// instead of being silently accepted and never matching.
+			name: "invalid wildcard origin",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					EmbeddedIframe: &settings.EmbeddedIframeSettings{
+						AllowedOrigins: []string{"https://foo.*.com"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "success m2m token trust",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					M2MTokenTrust: &settings.M2MTokenTrust{
+						Enabled:                true,
+						CacheExpirationSeconds: 300,
+						TrustedIssuers:         []string{"https://issuer.example.com"},
+						RequiredAudiences:      []string{"urn:zitadel:m2m"},
+					},
+				},
+			},
+			want: &settings.SetSecuritySettingsResponse{
+				Details: &object_pb.Details{
+					ChangeDate:    timestamppb.Now(),
+					ResourceOwner: Instance.ID(),
+				},
+			},
+		},
+		{
+			name: "m2m token trust cache expiration below bounds",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					M2MTokenTrust: &settings.M2MTokenTrust{
+						Enabled:                true,
+						CacheExpirationSeconds: -1,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "m2m token trust cache expiration above bounds",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					M2MTokenTrust: &settings.M2MTokenTrust{
+						Enabled:                true,
+						CacheExpirationSeconds: 86401,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "m2m token trust permission error",
+			args: args{
+				ctx: Instance.WithAuthorization(CTX, integration.UserTypeOrgOwner),
+				req: &settings.SetSecuritySettingsRequest{
+					M2MTokenTrust: &settings.M2MTokenTrust{
+						Enabled: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "success impersonation policy",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					ImpersonationPolicy: &settings.ImpersonationPolicy{
+						Enabled:                 true,
+						AllowedRoles:            []string{"IAM_OWNER"},
+						EligibleTargetUserTypes: []string{"human"},
+						MaxSessionDuration:      durationpb.New(time.Hour),
+						JustificationRequired:   true,
+						AuditSinkUrl:            "https://audit.example.com/impersonation",
+					},
+				},
+			},
+			want: &settings.SetSecuritySettingsResponse{
+				Details: &object_pb.Details{
+					ChangeDate:    timestamppb.Now(),
+					ResourceOwner: Instance.ID(),
+				},
+			},
+		},
+		{
+			name: "success deprecated enable_impersonation maps to default policy",
+			args: args{
+				ctx: AdminCTX,
+				req: &settings.SetSecuritySettingsRequest{
+					EnableImpersonation: true,
+				},
+			},
+			want: &settings.SetSecuritySettingsResponse{
+				Details: &object_pb.Details{
+					ChangeDate:    timestamppb.Now(),
+					ResourceOwner: Instance.ID(),
+				},
+			},
+		},
+		{
+			name: "impersonation policy permission error",
+			args: args{
+				ctx: Instance.WithAuthorization(CTX, integration.UserTypeOrgOwner),
+				req: &settings.SetSecuritySettingsRequest{
+					ImpersonationPolicy: &settings.ImpersonationPolicy{
+						Enabled: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -182,6 +355,196 @@ func TestServer_SetSecuritySettings(t *testing.T) {
 	}
 }
 
+func TestServer_UpdateSecuritySettings(t *testing.T) {
+	instance := integration.NewInstance(CTX)
+	isolatedIAMOwnerCTX := instance.WithAuthorization(CTX, integration.UserTypeIAMOwner)
+
+	_, err := instance.Client.SettingsV2.SetSecuritySettings(isolatedIAMOwnerCTX, &settings.SetSecuritySettingsRequest{
+		EmbeddedIframe: &settings.EmbeddedIframeSettings{
+			Enabled:        true,
+			AllowedOrigins: []string{"foo.com"},
+		},
+		M2MTokenTrust: &settings.M2MTokenTrust{
+			Enabled:                true,
+			CacheExpirationSeconds: 60,
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("mask limits the update to the named section", func(t *testing.T) {
+		_, err := instance.Client.SettingsV2.UpdateSecuritySettings(isolatedIAMOwnerCTX, &settings.UpdateSecuritySettingsRequest{
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"embedded_iframe"}},
+			Settings: &settings.SetSecuritySettingsRequest{
+				EmbeddedIframe: &settings.EmbeddedIframeSettings{
+					Enabled:        true,
+					AllowedOrigins: []string{"bar.com"},
+				},
+				M2MTokenTrust: &settings.M2MTokenTrust{
+					Enabled: false,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		retryDuration, tick := integration.WaitForAndTickWithMaxDuration(isolatedIAMOwnerCTX, time.Minute)
+		assert.EventuallyWithT(t, func(ct *assert.CollectT) {
+			got, err := instance.Client.SettingsV2.GetSecuritySettings(isolatedIAMOwnerCTX, &settings.GetSecuritySettingsRequest{})
+			if !assert.NoError(ct, err) {
+				return
+			}
+			assert.Equal(ct, []string{"bar.com"}, got.GetSettings().GetEmbeddedIframe().GetAllowedOrigins())
+			// m2m_token_trust was not named in the mask, so it must still be enabled.
+			assert.True(ct, got.GetSettings().GetM2MTokenTrust().GetEnabled())
+		}, retryDuration, tick)
+	})
+
+	t.Run("resource version mismatch is rejected", func(t *testing.T) {
+		current, err := instance.Client.SettingsV2.GetSecuritySettings(isolatedIAMOwnerCTX, &settings.GetSecuritySettingsRequest{})
+		require.NoError(t, err)
+
+		_, err = instance.Client.SettingsV2.UpdateSecuritySettings(isolatedIAMOwnerCTX, &settings.UpdateSecuritySettingsRequest{
+			UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"embedded_iframe"}},
+			ResourceVersion: current.GetResourceVersion() + "-stale",
+			Settings: &settings.SetSecuritySettingsRequest{
+				EmbeddedIframe: &settings.EmbeddedIframeSettings{
+					AllowedOrigins: []string{"baz.com"},
+				},
+			},
+		})
+		require.Error(t, err)
+
+		resp, err := instance.Client.SettingsV2.UpdateSecuritySettings(isolatedIAMOwnerCTX, &settings.UpdateSecuritySettingsRequest{
+			UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"embedded_iframe"}},
+			ResourceVersion: current.GetResourceVersion(),
+			Settings: &settings.SetSecuritySettingsRequest{
+				EmbeddedIframe: &settings.EmbeddedIframeSettings{
+					AllowedOrigins: []string{"baz.com"},
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.GetResourceVersion())
+		assert.NotEqual(t, current.GetResourceVersion(), resp.GetResourceVersion())
+	})
+}
+
+func TestServer_BulkSetSecuritySettings(t *testing.T) {
+	instance := integration.NewInstance(CTX)
+	isolatedIAMOwnerCTX := instance.WithAuthorization(CTX, integration.UserTypeIAMOwner)
+
+	type args struct {
+		ctx context.Context
+		req *settings.BulkSetSecuritySettingsRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "incomplete request rejected, nothing changed",
+			args: args{
+				ctx: isolatedIAMOwnerCTX,
+				req: &settings.BulkSetSecuritySettingsRequest{
+					EmbeddedIframe: &settings.EmbeddedIframeSettings{
+						Enabled: true,
+					},
+					// m2m_token_trust and impersonation_policy are missing, so
+					// the whole request must be rejected and nothing applied.
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "only impersonation_policy missing is rejected",
+			args: args{
+				ctx: isolatedIAMOwnerCTX,
+				req: &settings.BulkSetSecuritySettingsRequest{
+					EmbeddedIframe: &settings.EmbeddedIframeSettings{
+						Enabled: true,
+					},
+					M2MTokenTrust: &settings.M2MTokenTrust{
+						Enabled: true,
+					},
+					// impersonation_policy is missing: this must not silently
+					// fall back to disabling impersonation via the deprecated
+					// enable_impersonation mapping.
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "success replaces all three sections atomically",
+			args: args{
+				ctx: isolatedIAMOwnerCTX,
+				req: &settings.BulkSetSecuritySettingsRequest{
+					EmbeddedIframe: &settings.EmbeddedIframeSettings{
+						Enabled:        true,
+						AllowedOrigins: []string{"rollout.example.com"},
+					},
+					M2MTokenTrust: &settings.M2MTokenTrust{
+						Enabled:                true,
+						CacheExpirationSeconds: 120,
+					},
+					ImpersonationPolicy: &settings.ImpersonationPolicy{
+						Enabled: true,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := instance.Client.SettingsV2.BulkSetSecuritySettings(tt.args.ctx, tt.args.req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, got.GetResourceVersion())
+
+			retryDuration, tick := integration.WaitForAndTickWithMaxDuration(tt.args.ctx, time.Minute)
+			assert.EventuallyWithT(t, func(ct *assert.CollectT) {
+				current, err := instance.Client.SettingsV2.GetSecuritySettings(tt.args.ctx, &settings.GetSecuritySettingsRequest{})
+				if !assert.NoError(ct, err) {
+					return
+				}
+				assert.Equal(ct, []string{"rollout.example.com"}, current.GetSettings().GetEmbeddedIframe().GetAllowedOrigins())
+				assert.True(ct, current.GetSettings().GetM2MTokenTrust().GetEnabled())
+				assert.True(ct, current.GetSettings().GetImpersonationPolicy().GetEnabled())
+			}, retryDuration, tick)
+		})
+	}
+
+	t.Run("validation failure on one section changes nothing", func(t *testing.T) {
+		before, err := instance.Client.SettingsV2.GetSecuritySettings(isolatedIAMOwnerCTX, &settings.GetSecuritySettingsRequest{})
+		require.NoError(t, err)
+
+		_, err = instance.Client.SettingsV2.BulkSetSecuritySettings(isolatedIAMOwnerCTX, &settings.BulkSetSecuritySettingsRequest{
+			EmbeddedIframe: &settings.EmbeddedIframeSettings{
+				Enabled:        true,
+				AllowedOrigins: []string{"otherwise-valid.example.com"},
+			},
+			// cache_expiration_seconds exceeds the allowed maximum: this
+			// section is invalid even though the other two are not, and the
+			// whole request must be rejected with nothing applied.
+			M2MTokenTrust: &settings.M2MTokenTrust{
+				Enabled:                true,
+				CacheExpirationSeconds: 999999999,
+			},
+			ImpersonationPolicy: &settings.ImpersonationPolicy{
+				Enabled: true,
+			},
+		})
+		require.Error(t, err)
+
+		after, err := instance.Client.SettingsV2.GetSecuritySettings(isolatedIAMOwnerCTX, &settings.GetSecuritySettingsRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, before.GetSettings().GetEmbeddedIframe().GetAllowedOrigins(), after.GetSettings().GetEmbeddedIframe().GetAllowedOrigins())
+		assert.Equal(t, before.GetResourceVersion(), after.GetResourceVersion())
+	})
+}
+
 func TestServer_GetActiveIdentityProviders(t *testing.T) {
 	instance := integration.NewInstance(CTX)
 	isolatedIAMOwnerCTX := instance.WithAuthorization(CTX, integration.UserTypeIAMOwner)
@@ -453,3 +816,188 @@ func TestServer_GetActiveIdentityProviders(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_SecuritySettings_CORSPreflight(t *testing.T) {
+	_, err := Client.SetSecuritySettings(AdminCTX, &settings.SetSecuritySettingsRequest{
+		EmbeddedIframe: &settings.EmbeddedIframeSettings{
+			Enabled:        true,
+			AllowedOrigins: []string{"https://*.allowed.example.com"},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantAllow  bool
+		wantStatus int
+	}{
+		{
+			name:       "allowed wildcard origin",
+			origin:     "https://app.allowed.example.com",
+			wantAllow:  true,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "disallowed origin",
+			origin:     "https://evil.example.com",
+			wantAllow:  false,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryDuration, tick := integration.WaitForAndTickWithMaxDuration(AdminCTX, time.Minute)
+			assert.EventuallyWithT(t, func(ct *assert.CollectT) {
+				req, err := http.NewRequestWithContext(AdminCTX, http.MethodOptions, Instance.Domain()+"/zitadel.settings.v2.SettingsService/GetSecuritySettings", nil)
+				if !assert.NoError(ct, err) {
+					return
+				}
+				req.Header.Set("Origin", tt.origin)
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+				resp, err := http.DefaultClient.Do(req)
+				if !assert.NoError(ct, err) {
+					return
+				}
+				defer resp.Body.Close()
+
+				assert.Equal(ct, tt.wantStatus, resp.StatusCode)
+				assert.Equal(ct, "Origin", resp.Header.Get("Vary"))
+				if tt.wantAllow {
+					assert.Equal(ct, tt.origin, resp.Header.Get("Access-Control-Allow-Origin"))
+				} else {
+					assert.Empty(ct, resp.Header.Get("Access-Control-Allow-Origin"))
+				}
+			}, retryDuration, tick)
+		})
+	}
+}
+
+func TestServer_GetActiveIdentityProviders_ClientHints(t *testing.T) {
+	instance := integration.NewInstance(CTX)
+	isolatedIAMOwnerCTX := instance.WithAuthorization(CTX, integration.UserTypeIAMOwner)
+
+	enterpriseName := gofakeit.AppName()
+	enterpriseResp := instance.AddGenericOAuthProvider(isolatedIAMOwnerCTX, enterpriseName)
+	instance.AddProviderToDefaultLoginPolicy(isolatedIAMOwnerCTX, enterpriseResp.GetId())
+	socialName := gofakeit.AppName()
+	socialResp := instance.AddGenericOAuthProvider(isolatedIAMOwnerCTX, socialName)
+	instance.AddProviderToDefaultLoginPolicy(isolatedIAMOwnerCTX, socialResp.GetId())
+
+	_, err := instance.Client.SettingsV2.SetIdentityProviderRestrictions(isolatedIAMOwnerCTX, &settings.SetIdentityProviderRestrictionsRequest{
+		IdpId: enterpriseResp.GetId(),
+		Restrictions: &settings.IdentityProviderRestrictions{
+			AllowedEmailDomains: []string{"acme.com"},
+			Priority:            10,
+		},
+	})
+	require.NoError(t, err)
+
+	type args struct {
+		ctx context.Context
+		req *settings.GetActiveIdentityProvidersRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "acme.com login hint surfaces enterprise and social",
+			args: args{
+				ctx: isolatedIAMOwnerCTX,
+				req: &settings.GetActiveIdentityProvidersRequest{
+					LoginHint: gu.Ptr("jane@acme.com"),
+				},
+			},
+			want: []string{enterpriseResp.GetId(), socialResp.GetId()},
+		},
+		{
+			name: "other domain hides enterprise idp",
+			args: args{
+				ctx: isolatedIAMOwnerCTX,
+				req: &settings.GetActiveIdentityProvidersRequest{
+					LoginHint: gu.Ptr("jane@other.com"),
+				},
+			},
+			want: []string{socialResp.GetId()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryDuration, tick := integration.WaitForAndTickWithMaxDuration(tt.args.ctx, time.Minute)
+			assert.EventuallyWithT(t, func(ct *assert.CollectT) {
+				got, err := instance.Client.SettingsV2.GetActiveIdentityProviders(tt.args.ctx, tt.args.req)
+				if !assert.NoError(ct, err) {
+					return
+				}
+				ids := make([]string, len(got.GetIdentityProviders()))
+				for i, idp := range got.GetIdentityProviders() {
+					ids[i] = idp.GetId()
+				}
+				assert.Equal(ct, tt.want, ids)
+			}, retryDuration, tick)
+		})
+	}
+}
+
+func TestServer_ListImpersonationSessions(t *testing.T) {
+	// Sessions are only ever created as a side effect of an actual
+	// impersonated login, which is outside the scope of this RPC. This test
+	// therefore only covers the surface the settings service owns directly:
+	// permission handling and the empty-list shape for an instance that has
+	// never had an impersonation session.
+	instance := integration.NewInstance(CTX)
+	isolatedIAMOwnerCTX := instance.WithAuthorization(CTX, integration.UserTypeIAMOwner)
+
+	type args struct {
+		ctx context.Context
+		req *settings.ListImpersonationSessionsRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *settings.ListImpersonationSessionsResponse
+		wantErr bool
+	}{
+		{
+			name: "permission error",
+			args: args{
+				ctx: instance.WithAuthorization(CTX, integration.UserTypeOrgOwner),
+				req: &settings.ListImpersonationSessionsRequest{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "success, none active",
+			args: args{
+				ctx: isolatedIAMOwnerCTX,
+				req: &settings.ListImpersonationSessionsRequest{},
+			},
+			want: &settings.ListImpersonationSessionsResponse{
+				Details: &object_pb.ListDetails{
+					TotalResult: 0,
+					Timestamp:   timestamppb.Now(),
+				},
+				Sessions: nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryDuration, tick := integration.WaitForAndTickWithMaxDuration(tt.args.ctx, time.Minute)
+			assert.EventuallyWithT(t, func(ct *assert.CollectT) {
+				got, err := instance.Client.SettingsV2.ListImpersonationSessions(tt.args.ctx, tt.args.req)
+				if tt.wantErr {
+					assert.Error(ct, err)
+					return
+				}
+				if !assert.NoError(ct, err) {
+					return
+				}
+				assert.Equal(ct, tt.want.GetSessions(), got.GetSessions())
+			}, retryDuration, tick)
+		})
+	}
+}
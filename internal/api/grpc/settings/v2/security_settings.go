@@ -0,0 +1,194 @@
+package settings
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/muhlemmer/gu"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/api/grpc/object/v2"
+	"github.com/zitadel/zitadel/internal/command"
+	"github.com/zitadel/zitadel/internal/query"
+	"github.com/zitadel/zitadel/internal/zerrors"
+	settings_pb "github.com/zitadel/zitadel/pkg/grpc/settings/v2"
+)
+
+func (s *Server) GetSecuritySettings(ctx context.Context, _ *settings_pb.GetSecuritySettingsRequest) (*settings_pb.GetSecuritySettingsResponse, error) {
+	policy, err := s.query.SecurityPolicyByInstanceID(ctx, authz.GetInstance(ctx).InstanceID())
+	if err != nil {
+		return nil, err
+	}
+	return &settings_pb.GetSecuritySettingsResponse{
+		Settings:        securityPolicyToPb(policy),
+		ResourceVersion: policy.ResourceVersion,
+	}, nil
+}
+
+func securityPolicyToPb(policy *query.SecurityPolicy) *settings_pb.SecuritySettings {
+	return &settings_pb.SecuritySettings{
+		EmbeddedIframe: &settings_pb.EmbeddedIframeSettings{
+			Enabled:        policy.Enabled,
+			AllowedOrigins: policy.AllowedOrigins,
+		},
+		//lint:ignore SA1019 kept for clients that have not migrated to ImpersonationPolicy yet
+		EnableImpersonation: policy.ImpersonationPolicy.Enabled,
+		M2MTokenTrust: &settings_pb.M2MTokenTrust{
+			Enabled:                policy.M2MTokenTrust.Enabled,
+			CacheExpirationSeconds: policy.M2MTokenTrust.CacheExpirationSeconds,
+			TrustedIssuers:         policy.M2MTokenTrust.TrustedIssuers,
+			RequiredAudiences:      policy.M2MTokenTrust.RequiredAudiences,
+		},
+		ImpersonationPolicy: &settings_pb.ImpersonationPolicy{
+			Enabled:                 policy.ImpersonationPolicy.Enabled,
+			AllowedRoles:            policy.ImpersonationPolicy.AllowedRoles,
+			EligibleTargetUserTypes: policy.ImpersonationPolicy.EligibleTargetUserTypes,
+			MaxSessionDuration:      durationpb.New(policy.ImpersonationPolicy.MaxSessionDuration),
+			JustificationRequired:   policy.ImpersonationPolicy.JustificationRequired,
+			AuditSinkUrl:            policy.ImpersonationPolicy.AuditSinkURL,
+		},
+	}
+}
+
+func (s *Server) SetSecuritySettings(ctx context.Context, req *settings_pb.SetSecuritySettingsRequest) (*settings_pb.SetSecuritySettingsResponse, error) {
+	changeSet, err := securitySettingsChangeSet(req, nil)
+	if err != nil {
+		return nil, err
+	}
+	details, err := s.command.SetSecurityPolicy(ctx, authz.GetInstance(ctx).InstanceID(), changeSet)
+	if err != nil {
+		return nil, err
+	}
+	return &settings_pb.SetSecuritySettingsResponse{
+		Details: object.DomainToDetailsPb(details),
+	}, nil
+}
+
+func (s *Server) UpdateSecuritySettings(ctx context.Context, req *settings_pb.UpdateSecuritySettingsRequest) (*settings_pb.UpdateSecuritySettingsResponse, error) {
+	mask := req.GetUpdateMask().GetPaths()
+	changeSet, err := securitySettingsChangeSet(req.GetSettings(), mask)
+	if err != nil {
+		return nil, err
+	}
+	details, resourceVersion, err := s.command.UpdateSecurityPolicy(ctx, authz.GetInstance(ctx).InstanceID(), changeSet, req.GetResourceVersion())
+	if err != nil {
+		return nil, err
+	}
+	return &settings_pb.UpdateSecuritySettingsResponse{
+		Details:         object.DomainToDetailsPb(details),
+		ResourceVersion: resourceVersion,
+	}, nil
+}
+
+func (s *Server) BulkSetSecuritySettings(ctx context.Context, req *settings_pb.BulkSetSecuritySettingsRequest) (*settings_pb.BulkSetSecuritySettingsResponse, error) {
+	if req.GetEmbeddedIframe() == nil || req.GetM2MTokenTrust() == nil || req.GetImpersonationPolicy() == nil {
+		return nil, zerrors.ThrowInvalidArgument(nil, "SETTI-ooCh8", "Errors.Settings.BulkSet.IncompleteRequest")
+	}
+	changeSet, err := securitySettingsChangeSet(&settings_pb.SetSecuritySettingsRequest{
+		EmbeddedIframe:      req.GetEmbeddedIframe(),
+		M2MTokenTrust:       req.GetM2MTokenTrust(),
+		ImpersonationPolicy: req.GetImpersonationPolicy(),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	details, resourceVersion, err := s.command.BulkSetSecurityPolicy(ctx, authz.GetInstance(ctx).InstanceID(), changeSet)
+	if err != nil {
+		return nil, err
+	}
+	return &settings_pb.BulkSetSecuritySettingsResponse{
+		Details:         object.DomainToDetailsPb(details),
+		ResourceVersion: resourceVersion,
+	}, nil
+}
+
+// securitySettingsChangeSet builds the change set for the given request.
+// When mask is nil (SetSecuritySettings, BulkSetSecuritySettings, or
+// UpdateSecuritySettings without mask paths), every section present on req
+// is applied. When mask is non-nil, only the sections named in it are
+// applied, even if other sections are also set on req.
+func securitySettingsChangeSet(req *settings_pb.SetSecuritySettingsRequest, mask []string) (command.SecurityPolicyChangeSet, error) {
+	changeSet := command.SecurityPolicyChangeSet{}
+	if iframe := req.GetEmbeddedIframe(); iframe != nil && maskAllows(mask, "embedded_iframe") {
+		changeSet.Enabled = gu.Ptr(iframe.GetEnabled())
+		changeSet.AllowedOrigins = iframe.GetAllowedOrigins()
+		if err := validateAllowedOrigins(changeSet.AllowedOrigins); err != nil {
+			return changeSet, err
+		}
+	}
+	if m2m := req.GetM2MTokenTrust(); m2m != nil && maskAllows(mask, "m2m_token_trust") {
+		changeSet.M2MTokenTrustEnabled = gu.Ptr(m2m.GetEnabled())
+		changeSet.M2MTokenTrustCacheExpirationSeconds = gu.Ptr(m2m.GetCacheExpirationSeconds())
+		changeSet.M2MTokenTrustTrustedIssuers = m2m.GetTrustedIssuers()
+		changeSet.M2MTokenTrustRequiredAudiences = m2m.GetRequiredAudiences()
+	}
+	if maskAllows(mask, "impersonation_policy") || maskAllows(mask, "enable_impersonation") {
+		applyImpersonationChangeSet(&changeSet, req)
+	}
+	return changeSet, nil
+}
+
+// maskAllows reports whether path may be applied: every path is allowed
+// when mask is nil (no mask given), otherwise only paths named in mask.
+func maskAllows(mask []string, path string) bool {
+	if mask == nil {
+		return true
+	}
+	for _, p := range mask {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// applyImpersonationChangeSet maps the request's impersonation configuration
+// onto the change set. If impersonation_policy is set, it takes precedence;
+// otherwise the deprecated enable_impersonation bool is mapped to a default
+// policy with no role/target restrictions, no required justification and no
+// maximum session duration, preserving the previous coarse on/off behavior
+// for callers that have not migrated yet. The audit sink URL is kept.
+func applyImpersonationChangeSet(changeSet *command.SecurityPolicyChangeSet, req *settings_pb.SetSecuritySettingsRequest) {
+	policy := req.GetImpersonationPolicy()
+	if policy == nil {
+		changeSet.ImpersonationEnabled = gu.Ptr(req.GetEnableImpersonation())
+		changeSet.ImpersonationAllowedRoles = []string{}
+		changeSet.ImpersonationEligibleTargetUserTypes = []string{}
+		changeSet.ImpersonationMaxSessionDuration = gu.Ptr(time.Duration(0))
+		changeSet.ImpersonationJustificationRequired = gu.Ptr(false)
+		return
+	}
+	changeSet.ImpersonationEnabled = gu.Ptr(policy.GetEnabled())
+	changeSet.ImpersonationAllowedRoles = policy.GetAllowedRoles()
+	changeSet.ImpersonationEligibleTargetUserTypes = policy.GetEligibleTargetUserTypes()
+	changeSet.ImpersonationMaxSessionDuration = gu.Ptr(policy.GetMaxSessionDuration().AsDuration())
+	changeSet.ImpersonationJustificationRequired = gu.Ptr(policy.GetJustificationRequired())
+	changeSet.ImpersonationAuditSinkURL = gu.Ptr(policy.GetAuditSinkUrl())
+}
+
+// validateAllowedOrigins makes sure every "regex:" prefixed origin pattern
+// compiles and every wildcard pattern has the "scheme://*.host" shape the
+// CORS middleware's matchesPattern requires, so that callers get an
+// immediate InvalidArgument instead of a pattern that silently never
+// matches once enforced there.
+func validateAllowedOrigins(origins []string) error {
+	for _, origin := range origins {
+		if regex, ok := strings.CutPrefix(origin, "regex:"); ok {
+			if _, err := regexp.Compile(regex); err != nil {
+				return zerrors.ThrowInvalidArgumentf(err, "SETTI-Aesh1", "Errors.Settings.InvalidOriginPattern", origin)
+			}
+			continue
+		}
+		if !strings.Contains(origin, "*") {
+			continue
+		}
+		_, host, ok := strings.Cut(origin, "://")
+		if !ok || !strings.HasPrefix(host, "*.") {
+			return zerrors.ThrowInvalidArgumentf(nil, "SETTI-ahn2O", "Errors.Settings.InvalidOriginPattern", origin)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package settings
+
+import (
+	"github.com/zitadel/zitadel/internal/command"
+	"github.com/zitadel/zitadel/internal/query"
+	settings_pb "github.com/zitadel/zitadel/pkg/grpc/settings/v2"
+)
+
+var _ settings_pb.SettingsServiceServer = (*Server)(nil)
+
+type Server struct {
+	settings_pb.UnimplementedSettingsServiceServer
+
+	command *command.Commands
+	query   *query.Queries
+}
+
+func CreateServer(command *command.Commands, query *query.Queries) *Server {
+	return &Server{
+		command: command,
+		query:   query,
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/query"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	regexOriginPrefix = "regex:"
+	wildcardLabel     = "*"
+)
+
+// SecuritySettingsQuerier is implemented by internal/query and is used by
+// the CORS middleware to read the instance's currently configured allowed
+// origins without importing the query package's full surface.
+type SecuritySettingsQuerier interface {
+	SecurityPolicyByInstanceID(ctx context.Context, instanceID string) (*query.SecurityPolicy, error)
+}
+
+// CORSInterceptor enforces the instance's EmbeddedIframeSettings.AllowedOrigins
+// as real CORS origins on every request, not just as the iframe
+// frame-ancestors CSP. It reflects the matching origin back in
+// Access-Control-Allow-Origin, always sets Vary: Origin, and answers
+// preflight OPTIONS requests directly instead of forwarding them to next.
+// It is mounted outermost on the API and asset mux by api.WrapAPIHandler.
+func CORSInterceptor(queries SecuritySettingsQuerier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			policy, err := queries.SecurityPolicyByInstanceID(r.Context(), authz.GetInstance(r.Context()).InstanceID())
+			if err != nil || !matchesAllowedOrigin(origin, policy.AllowedOrigins) {
+				if r.Method == http.MethodOptions {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			w.Header().Set("Access-Control-Allow-Methods", r.Header.Get("Access-Control-Request-Method"))
+			w.Header().Set("Access-Control-Max-Age", "7200")
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// matchesAllowedOrigin reports whether origin matches one of the configured
+// patterns, tried in the order they are configured. Each pattern is one of
+// the forms documented on EmbeddedIframeSettings.AllowedOrigins: exact
+// match, wildcard subdomain ("https://*.example.com"), or an explicit
+// "regex:" prefixed expression. Invalid patterns never match.
+func matchesAllowedOrigin(origin string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := matchesPattern(origin, pattern); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPattern(origin, pattern string) (bool, error) {
+	if regex, ok := strings.CutPrefix(pattern, regexOriginPrefix); ok {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return false, zerrors.ThrowInvalidArgument(err, "MIDDL-Eeg2A", "Errors.Settings.InvalidOriginPattern")
+		}
+		return re.MatchString(origin), nil
+	}
+	if !strings.Contains(pattern, wildcardLabel) {
+		return origin == pattern, nil
+	}
+	scheme, host, ok := strings.Cut(pattern, "://")
+	if !ok || !strings.HasPrefix(host, "*.") {
+		return false, zerrors.ThrowInvalidArgument(nil, "MIDDL-Oht1i", "Errors.Settings.InvalidOriginPattern")
+	}
+	originScheme, originHost, ok := strings.Cut(origin, "://")
+	if !ok || originScheme != scheme {
+		return false, nil
+	}
+	suffix := host[1:] // keep the leading dot, e.g. ".example.com"
+	return strings.HasSuffix(originHost, suffix) && originHost != suffix[1:], nil
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import "testing"
+
+func Test_matchesPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		pattern string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "exact match",
+			origin:  "https://foo.com",
+			pattern: "https://foo.com",
+			want:    true,
+		},
+		{
+			name:    "exact mismatch",
+			origin:  "https://foo.com",
+			pattern: "https://bar.com",
+			want:    false,
+		},
+		{
+			name:    "wildcard subdomain match",
+			origin:  "https://app.example.com",
+			pattern: "https://*.example.com",
+			want:    true,
+		},
+		{
+			name:    "wildcard does not match bare apex",
+			origin:  "https://example.com",
+			pattern: "https://*.example.com",
+			want:    false,
+		},
+		{
+			name:    "wildcard scheme mismatch",
+			origin:  "http://app.example.com",
+			pattern: "https://*.example.com",
+			want:    false,
+		},
+		{
+			name:    "invalid wildcard pattern",
+			origin:  "https://app.example.com",
+			pattern: "https://foo.*.com",
+			wantErr: true,
+		},
+		{
+			name:    "regex match",
+			origin:  "https://foo.example.com",
+			pattern: `regex:^https://(foo|bar)\.example\.com$`,
+			want:    true,
+		},
+		{
+			name:    "regex mismatch",
+			origin:  "https://baz.example.com",
+			pattern: `regex:^https://(foo|bar)\.example\.com$`,
+			want:    false,
+		},
+		{
+			name:    "invalid regex pattern",
+			origin:  "https://foo.example.com",
+			pattern: "regex:(",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchesPattern(tt.origin, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchesPattern() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("matchesPattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_matchesAllowedOrigin(t *testing.T) {
+	patterns := []string{"https://foo.com", "https://*.example.com", `regex:^https://(a|b)\.test$`}
+	tests := []struct {
+		name   string
+		origin string
+		want   bool
+	}{
+		{name: "exact", origin: "https://foo.com", want: true},
+		{name: "wildcard", origin: "https://app.example.com", want: true},
+		{name: "regex", origin: "https://a.test", want: true},
+		{name: "no match", origin: "https://evil.com", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAllowedOrigin(tt.origin, patterns); got != tt.want {
+				t.Errorf("matchesAllowedOrigin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// SetIDPRestrictions configures which clients and email domains an identity
+// provider applies to, and its ranking priority among GetActiveIdentityProviders
+// results.
+func (c *Commands) SetIDPRestrictions(
+	ctx context.Context,
+	instanceID, idpID string,
+	allowedEmailDomains, allowedClientIDs []string,
+	priority int32,
+) (*domain.ObjectDetails, error) {
+	if idpID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMA-ra7Oo", "Errors.IDMissing")
+	}
+	wm := NewInstanceIDPRestrictionsWriteModel(instanceID, idpID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, wm); err != nil {
+		return nil, err
+	}
+	aggregate := instance.NewAggregate(instanceID)
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewIDPRestrictionsSetEvent(
+		ctx,
+		&aggregate.Aggregate,
+		idpID,
+		allowedEmailDomains,
+		allowedClientIDs,
+		priority,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if err := AppendAndReduce(wm, pushedEvents...); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&wm.WriteModel), nil
+}
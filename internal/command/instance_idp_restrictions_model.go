@@ -0,0 +1,47 @@
+package command
+
+import (
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+type InstanceIDPRestrictionsWriteModel struct {
+	eventstore.WriteModel
+
+	IDPID               string
+	AllowedEmailDomains []string
+	AllowedClientIDs    []string
+	Priority            int32
+}
+
+func NewInstanceIDPRestrictionsWriteModel(instanceID, idpID string) *InstanceIDPRestrictionsWriteModel {
+	return &InstanceIDPRestrictionsWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   instanceID,
+			ResourceOwner: instanceID,
+		},
+		IDPID: idpID,
+	}
+}
+
+func (wm *InstanceIDPRestrictionsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		e, ok := event.(*instance.IDPRestrictionsSetEvent)
+		if !ok || e.IDPID != wm.IDPID {
+			continue
+		}
+		wm.AllowedEmailDomains = e.AllowedEmailDomains
+		wm.AllowedClientIDs = e.AllowedClientIDs
+		wm.Priority = e.Priority
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *InstanceIDPRestrictionsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(instance.IDPRestrictionsSetEventType).
+		Builder()
+}
@@ -0,0 +1,143 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// StartImpersonationSession records the start of an impersonation session
+// and enforces the instance's ImpersonationPolicy: the impersonator's role
+// must be allowed, the target user type must be eligible, and a
+// justification must be supplied whenever the policy requires one. If the
+// policy configures an audit sink, the start event is streamed to it on a
+// best-effort basis.
+//
+// This is the enforcement point the request asked for, but nothing in this
+// codebase calls it yet: the login/session handler that actually issues an
+// impersonated session lives outside this tree, and it is that handler's
+// job to call StartImpersonationSession before issuing the session and
+// EndImpersonationSession when it is torn down.
+func (c *Commands) StartImpersonationSession(
+	ctx context.Context,
+	instanceID, impersonatorUserID, impersonatorRole, targetUserID, targetUserType, justification string,
+) (sessionID string, details *domain.ObjectDetails, err error) {
+	policyWM := NewInstanceSecurityPolicyWriteModel(instanceID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, policyWM); err != nil {
+		return "", nil, err
+	}
+	if !policyWM.EnableImpersonation {
+		return "", nil, zerrors.ThrowPermissionDenied(nil, "COMMA-oong6", "Errors.Impersonation.NotEnabled")
+	}
+	if len(policyWM.ImpersonationAllowedRoles) > 0 && !contains(policyWM.ImpersonationAllowedRoles, impersonatorRole) {
+		return "", nil, zerrors.ThrowPermissionDenied(nil, "COMMA-cai1A", "Errors.Impersonation.RoleNotAllowed")
+	}
+	if len(policyWM.ImpersonationEligibleTargetUserTypes) > 0 && !contains(policyWM.ImpersonationEligibleTargetUserTypes, targetUserType) {
+		return "", nil, zerrors.ThrowPermissionDenied(nil, "COMMA-Ue1ax", "Errors.Impersonation.TargetUserTypeNotEligible")
+	}
+	if policyWM.ImpersonationJustificationRequired && strings.TrimSpace(justification) == "" {
+		return "", nil, zerrors.ThrowInvalidArgument(nil, "COMMA-ahNg1", "Errors.Impersonation.JustificationRequired")
+	}
+
+	sessionID = c.idGenerator.Next()
+	startedAt := time.Now()
+	wm := NewInstanceImpersonationSessionWriteModel(instanceID, sessionID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, wm); err != nil {
+		return "", nil, err
+	}
+	aggregate := instance.NewAggregate(instanceID)
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewImpersonationSessionStartedEvent(
+		ctx, &aggregate.Aggregate, sessionID, impersonatorUserID, targetUserID, justification, startedAt,
+	))
+	if err != nil {
+		return "", nil, err
+	}
+	if err := AppendAndReduce(wm, pushedEvents...); err != nil {
+		return "", nil, err
+	}
+	streamImpersonationEvent(policyWM.ImpersonationAuditSinkURL, "session.started", sessionID, impersonatorUserID, targetUserID)
+	return sessionID, writeModelToObjectDetails(&wm.WriteModel), nil
+}
+
+// EndImpersonationSession records the end of a previously started
+// impersonation session and streams it to the configured audit sink.
+func (c *Commands) EndImpersonationSession(ctx context.Context, instanceID, sessionID string) (*domain.ObjectDetails, error) {
+	wm := NewInstanceImpersonationSessionWriteModel(instanceID, sessionID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, wm); err != nil {
+		return nil, err
+	}
+	if wm.StartedAt.IsZero() {
+		return nil, zerrors.ThrowNotFound(nil, "COMMA-ooD2u", "Errors.Impersonation.SessionNotFound")
+	}
+	if wm.Ended {
+		return writeModelToObjectDetails(&wm.WriteModel), nil
+	}
+	aggregate := instance.NewAggregate(instanceID)
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewImpersonationSessionEndedEvent(ctx, &aggregate.Aggregate, sessionID))
+	if err != nil {
+		return nil, err
+	}
+	if err := AppendAndReduce(wm, pushedEvents...); err != nil {
+		return nil, err
+	}
+	policyWM := NewInstanceSecurityPolicyWriteModel(instanceID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, policyWM); err == nil {
+		streamImpersonationEvent(policyWM.ImpersonationAuditSinkURL, "session.stopped", sessionID, wm.ImpersonatorUserID, wm.TargetUserID)
+	}
+	return writeModelToObjectDetails(&wm.WriteModel), nil
+}
+
+// impersonationAuditEvent is the payload posted to the configured audit
+// sink for a session.started/session.stopped notification.
+type impersonationAuditEvent struct {
+	Event              string `json:"event"`
+	SessionID          string `json:"sessionId"`
+	ImpersonatorUserID string `json:"impersonatorUserId"`
+	TargetUserID       string `json:"targetUserId"`
+}
+
+// streamImpersonationEvent posts a minimal JSON notification to the
+// configured audit sink. Delivery is best-effort: a sink that is
+// unreachable must never fail the impersonation session itself.
+func streamImpersonationEvent(sinkURL, event, sessionID, impersonatorUserID, targetUserID string) {
+	if sinkURL == "" {
+		return
+	}
+	payload, err := json.Marshal(impersonationAuditEvent{
+		Event:              event,
+		SessionID:          sessionID,
+		ImpersonatorUserID: impersonatorUserID,
+		TargetUserID:       targetUserID,
+	})
+	if err != nil {
+		return
+	}
+	go func() {
+		req, err := http.NewRequest(http.MethodPost, sinkURL, bytes.NewReader(payload))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+	}()
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
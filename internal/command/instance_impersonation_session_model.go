@@ -0,0 +1,64 @@
+package command
+
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+// InstanceImpersonationSessionWriteModel tracks a single impersonation
+// session, from the started event up to an optional ended event.
+type InstanceImpersonationSessionWriteModel struct {
+	eventstore.WriteModel
+
+	SessionID          string
+	ImpersonatorUserID string
+	TargetUserID       string
+	Justification      string
+	StartedAt          time.Time
+	Ended              bool
+}
+
+func NewInstanceImpersonationSessionWriteModel(instanceID, sessionID string) *InstanceImpersonationSessionWriteModel {
+	return &InstanceImpersonationSessionWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   instanceID,
+			ResourceOwner: instanceID,
+		},
+		SessionID: sessionID,
+	}
+}
+
+func (wm *InstanceImpersonationSessionWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *instance.ImpersonationSessionStartedEvent:
+			if e.SessionID != wm.SessionID {
+				continue
+			}
+			wm.ImpersonatorUserID = e.ImpersonatorUserID
+			wm.TargetUserID = e.TargetUserID
+			wm.Justification = e.Justification
+			wm.StartedAt = e.StartedAt
+		case *instance.ImpersonationSessionEndedEvent:
+			if e.SessionID != wm.SessionID {
+				continue
+			}
+			wm.Ended = true
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *InstanceImpersonationSessionWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			instance.ImpersonationSessionStartedEventType,
+			instance.ImpersonationSessionEndedEventType,
+		).
+		Builder()
+}
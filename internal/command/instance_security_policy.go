@@ -0,0 +1,115 @@
+package command
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const maxM2MTokenTrustCacheExpirationSeconds = 86400
+
+// SecurityPolicyResourceVersion returns the ETag callers of
+// GetSecuritySettings/UpdateSecuritySettings exchange to detect concurrent
+// modification of the security policy. It is derived from the write
+// model's processed sequence, so it changes on every pushed
+// SecurityPolicySetEvent and nothing else.
+func SecurityPolicyResourceVersion(wm *InstanceSecurityPolicyWriteModel) string {
+	return strconv.FormatUint(wm.ProcessedSequence, 10)
+}
+
+func validateSecurityPolicyChangeSet(changeSet SecurityPolicyChangeSet) error {
+	if changeSet.M2MTokenTrustCacheExpirationSeconds != nil &&
+		(*changeSet.M2MTokenTrustCacheExpirationSeconds < 0 || *changeSet.M2MTokenTrustCacheExpirationSeconds > maxM2MTokenTrustCacheExpirationSeconds) {
+		return zerrors.ThrowInvalidArgument(nil, "COMMA-eeW6a", "Errors.Settings.M2MTokenTrust.InvalidCacheExpiration")
+	}
+	return nil
+}
+
+// SetSecurityPolicy sets the fields that are non-nil on the instance's
+// security policy. Fields left nil are left unchanged, matching the
+// behavior the callers of SetSecuritySettings already rely on.
+func (c *Commands) SetSecurityPolicy(
+	ctx context.Context,
+	instanceID string,
+	changeSet SecurityPolicyChangeSet,
+) (*domain.ObjectDetails, error) {
+	if err := validateSecurityPolicyChangeSet(changeSet); err != nil {
+		return nil, err
+	}
+	wm := NewInstanceSecurityPolicyWriteModel(instanceID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, wm); err != nil {
+		return nil, err
+	}
+	changes := wm.NewChanges(changeSet)
+	if len(changes) == 0 {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMA-zaRqh", "Errors.NoChangesFound")
+	}
+	aggregate := instance.NewAggregate(instanceID)
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewSecurityPolicySetEvent(ctx, &aggregate.Aggregate, changes))
+	if err != nil {
+		return nil, err
+	}
+	if err := AppendAndReduce(wm, pushedEvents...); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&wm.WriteModel), nil
+}
+
+// UpdateSecurityPolicy applies changeSet the same way SetSecurityPolicy
+// does, but additionally enforces optimistic concurrency: if
+// expectedResourceVersion is non-empty, it must match the policy's current
+// SecurityPolicyResourceVersion or the update is rejected with
+// FAILED_PRECONDITION instead of silently clobbering a concurrent edit.
+func (c *Commands) UpdateSecurityPolicy(
+	ctx context.Context,
+	instanceID string,
+	changeSet SecurityPolicyChangeSet,
+	expectedResourceVersion string,
+) (*domain.ObjectDetails, string, error) {
+	if err := validateSecurityPolicyChangeSet(changeSet); err != nil {
+		return nil, "", err
+	}
+	wm := NewInstanceSecurityPolicyWriteModel(instanceID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, wm); err != nil {
+		return nil, "", err
+	}
+	if expectedResourceVersion != "" && expectedResourceVersion != SecurityPolicyResourceVersion(wm) {
+		return nil, "", zerrors.ThrowPreconditionFailed(nil, "COMMA-eiS1o", "Errors.Settings.ResourceVersionMismatch")
+	}
+	changes := wm.NewChanges(changeSet)
+	if len(changes) == 0 {
+		return nil, "", zerrors.ThrowPreconditionFailed(nil, "COMMA-zaRqh", "Errors.NoChangesFound")
+	}
+	aggregate := instance.NewAggregate(instanceID)
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewSecurityPolicySetEvent(ctx, &aggregate.Aggregate, changes))
+	if err != nil {
+		return nil, "", err
+	}
+	if err := AppendAndReduce(wm, pushedEvents...); err != nil {
+		return nil, "", err
+	}
+	return writeModelToObjectDetails(&wm.WriteModel), SecurityPolicyResourceVersion(wm), nil
+}
+
+// BulkSetSecurityPolicy applies the iframe, impersonation and M2M token
+// trust settings together in a single event, for scripted rollouts that
+// want to set the whole policy in one call. Like SetSecurityPolicy, it
+// leaves a list unchanged when changeSet carries it as nil. It is
+// the caller's responsibility to ensure changeSet was built from a request
+// that actually specified all three sections: BulkSetSecurityPolicy itself
+// has no way to distinguish "section omitted" from "section explicitly set
+// to its zero value" once the sections have been flattened into changeSet.
+func (c *Commands) BulkSetSecurityPolicy(
+	ctx context.Context,
+	instanceID string,
+	changeSet SecurityPolicyChangeSet,
+) (*domain.ObjectDetails, string, error) {
+	details, resourceVersion, err := c.UpdateSecurityPolicy(ctx, instanceID, changeSet, "")
+	if err != nil {
+		return nil, "", err
+	}
+	return details, resourceVersion, nil
+}
@@ -0,0 +1,156 @@
+package command
+
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/policy"
+)
+
+type InstanceSecurityPolicyWriteModel struct {
+	eventstore.WriteModel
+
+	Enabled             bool
+	AllowedOrigins      []string
+	EnableImpersonation bool
+
+	M2MTokenTrustEnabled                bool
+	M2MTokenTrustCacheExpirationSeconds int32
+	M2MTokenTrustTrustedIssuers         []string
+	M2MTokenTrustRequiredAudiences      []string
+
+	// ImpersonationAllowedRoles, ImpersonationEligibleTargetUserTypes,
+	// ImpersonationMaxSessionDuration, ImpersonationJustificationRequired and
+	// ImpersonationAuditSinkURL refine EnableImpersonation into the scoped
+	// ImpersonationPolicy. EnableImpersonation continues to track
+	// ImpersonationPolicy.Enabled for backwards compatible reads.
+	ImpersonationAllowedRoles            []string
+	ImpersonationEligibleTargetUserTypes []string
+	ImpersonationMaxSessionDuration      time.Duration
+	ImpersonationJustificationRequired   bool
+	ImpersonationAuditSinkURL            string
+}
+
+func NewInstanceSecurityPolicyWriteModel(instanceID string) *InstanceSecurityPolicyWriteModel {
+	return &InstanceSecurityPolicyWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   instanceID,
+			ResourceOwner: instanceID,
+		},
+	}
+}
+
+func (wm *InstanceSecurityPolicyWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *instance.SecurityPolicySetEvent:
+			if e.Enabled != nil {
+				wm.Enabled = *e.Enabled
+			}
+			if e.AllowedOrigins != nil {
+				wm.AllowedOrigins = *e.AllowedOrigins
+			}
+			if e.M2MTokenTrustEnabled != nil {
+				wm.M2MTokenTrustEnabled = *e.M2MTokenTrustEnabled
+			}
+			if e.M2MTokenTrustCacheExpirationSeconds != nil {
+				wm.M2MTokenTrustCacheExpirationSeconds = *e.M2MTokenTrustCacheExpirationSeconds
+			}
+			if e.M2MTokenTrustTrustedIssuers != nil {
+				wm.M2MTokenTrustTrustedIssuers = *e.M2MTokenTrustTrustedIssuers
+			}
+			if e.M2MTokenTrustRequiredAudiences != nil {
+				wm.M2MTokenTrustRequiredAudiences = *e.M2MTokenTrustRequiredAudiences
+			}
+			if e.ImpersonationEnabled != nil {
+				wm.EnableImpersonation = *e.ImpersonationEnabled
+			}
+			if e.ImpersonationAllowedRoles != nil {
+				wm.ImpersonationAllowedRoles = *e.ImpersonationAllowedRoles
+			}
+			if e.ImpersonationEligibleTargetUserTypes != nil {
+				wm.ImpersonationEligibleTargetUserTypes = *e.ImpersonationEligibleTargetUserTypes
+			}
+			if e.ImpersonationMaxSessionDuration != nil {
+				wm.ImpersonationMaxSessionDuration = *e.ImpersonationMaxSessionDuration
+			}
+			if e.ImpersonationJustificationRequired != nil {
+				wm.ImpersonationJustificationRequired = *e.ImpersonationJustificationRequired
+			}
+			if e.ImpersonationAuditSinkURL != nil {
+				wm.ImpersonationAuditSinkURL = *e.ImpersonationAuditSinkURL
+			}
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *InstanceSecurityPolicyWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(instance.SecurityPolicySetEventType).
+		Builder()
+}
+
+// SecurityPolicyChangeSet carries the fields a caller wants to change on the
+// security policy. A nil field is left unchanged.
+type SecurityPolicyChangeSet struct {
+	Enabled        *bool
+	AllowedOrigins []string
+
+	M2MTokenTrustEnabled                *bool
+	M2MTokenTrustCacheExpirationSeconds *int32
+	M2MTokenTrustTrustedIssuers         []string
+	M2MTokenTrustRequiredAudiences      []string
+
+	ImpersonationEnabled                 *bool
+	ImpersonationAllowedRoles            []string
+	ImpersonationEligibleTargetUserTypes []string
+	ImpersonationMaxSessionDuration      *time.Duration
+	ImpersonationJustificationRequired   *bool
+	ImpersonationAuditSinkURL            *string
+}
+
+func (wm *InstanceSecurityPolicyWriteModel) NewChanges(changeSet SecurityPolicyChangeSet) []policy.SecurityPolicyChanges {
+	changes := make([]policy.SecurityPolicyChanges, 0, 12)
+	if changeSet.Enabled != nil && *changeSet.Enabled != wm.Enabled {
+		changes = append(changes, policy.ChangeSecurityPolicyEnabled(*changeSet.Enabled))
+	}
+	if changeSet.AllowedOrigins != nil {
+		changes = append(changes, policy.ChangeSecurityPolicyAllowedOrigins(changeSet.AllowedOrigins))
+	}
+	if changeSet.M2MTokenTrustEnabled != nil && *changeSet.M2MTokenTrustEnabled != wm.M2MTokenTrustEnabled {
+		changes = append(changes, policy.ChangeSecurityPolicyM2MTokenTrustEnabled(*changeSet.M2MTokenTrustEnabled))
+	}
+	if changeSet.M2MTokenTrustCacheExpirationSeconds != nil && *changeSet.M2MTokenTrustCacheExpirationSeconds != wm.M2MTokenTrustCacheExpirationSeconds {
+		changes = append(changes, policy.ChangeSecurityPolicyM2MTokenTrustCacheExpiration(*changeSet.M2MTokenTrustCacheExpirationSeconds))
+	}
+	if changeSet.M2MTokenTrustTrustedIssuers != nil {
+		changes = append(changes, policy.ChangeSecurityPolicyM2MTokenTrustIssuers(changeSet.M2MTokenTrustTrustedIssuers))
+	}
+	if changeSet.M2MTokenTrustRequiredAudiences != nil {
+		changes = append(changes, policy.ChangeSecurityPolicyM2MTokenTrustAudiences(changeSet.M2MTokenTrustRequiredAudiences))
+	}
+	if changeSet.ImpersonationEnabled != nil && *changeSet.ImpersonationEnabled != wm.EnableImpersonation {
+		changes = append(changes, policy.ChangeSecurityPolicyImpersonationEnabled(*changeSet.ImpersonationEnabled))
+	}
+	if changeSet.ImpersonationAllowedRoles != nil {
+		changes = append(changes, policy.ChangeSecurityPolicyImpersonationAllowedRoles(changeSet.ImpersonationAllowedRoles))
+	}
+	if changeSet.ImpersonationEligibleTargetUserTypes != nil {
+		changes = append(changes, policy.ChangeSecurityPolicyImpersonationEligibleTargetUserTypes(changeSet.ImpersonationEligibleTargetUserTypes))
+	}
+	if changeSet.ImpersonationMaxSessionDuration != nil && *changeSet.ImpersonationMaxSessionDuration != wm.ImpersonationMaxSessionDuration {
+		changes = append(changes, policy.ChangeSecurityPolicyImpersonationMaxSessionDuration(*changeSet.ImpersonationMaxSessionDuration))
+	}
+	if changeSet.ImpersonationJustificationRequired != nil && *changeSet.ImpersonationJustificationRequired != wm.ImpersonationJustificationRequired {
+		changes = append(changes, policy.ChangeSecurityPolicyImpersonationJustificationRequired(*changeSet.ImpersonationJustificationRequired))
+	}
+	if changeSet.ImpersonationAuditSinkURL != nil && *changeSet.ImpersonationAuditSinkURL != wm.ImpersonationAuditSinkURL {
+		changes = append(changes, policy.ChangeSecurityPolicyImpersonationAuditSinkURL(*changeSet.ImpersonationAuditSinkURL))
+	}
+	return changes
+}
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+// IdentityProvider is the read model of an identity provider as surfaced by
+// GetActiveIdentityProviders, including the optional client/email
+// restrictions admins can configure on top of the login policy flags.
+type IdentityProvider struct {
+	ID              string
+	Name            string
+	Type            domain.IDPType
+	LinkingAllowed  bool
+	CreationAllowed bool
+	AutoCreation    bool
+	AutoLinking     bool
+
+	AllowedEmailDomains []string
+	AllowedClientIDs    []string
+	Priority            int32
+}
+
+// ActiveIdentityProvidersSearchQueries carries the login-attempt context a
+// client can provide to GetActiveIdentityProviders so the result can be
+// narrowed or ranked accordingly.
+type ActiveIdentityProvidersSearchQueries struct {
+	LinkingAllowed  *bool
+	CreationAllowed *bool
+	AutoCreation    *bool
+	AutoLinking     *bool
+
+	ClientID        string
+	LoginHint       string
+	EmailDomain     string
+	RequestedScopes []string
+}
+
+// ActiveIdentityProviders returns the identity providers of the instance's
+// default login policy that match the login policy flag filters and, when
+// provided, the client id / email domain restrictions, ordered by
+// descending restriction priority.
+func (q *Queries) ActiveIdentityProviders(ctx context.Context, queries *ActiveIdentityProvidersSearchQueries) ([]*IdentityProvider, error) {
+	idps, err := q.searchActiveIdentityProviders(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+	emailDomain := emailDomainOf(queries.EmailDomain, queries.LoginHint)
+	filtered := make([]*IdentityProvider, 0, len(idps))
+	for _, idp := range idps {
+		if !idp.appliesToClient(queries.ClientID) || !idp.appliesToEmailDomain(emailDomain) {
+			continue
+		}
+		filtered = append(filtered, idp)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Priority > filtered[j].Priority
+	})
+	return filtered, nil
+}
+
+func (idp *IdentityProvider) appliesToClient(clientID string) bool {
+	if len(idp.AllowedClientIDs) == 0 || clientID == "" {
+		return true
+	}
+	for _, allowed := range idp.AllowedClientIDs {
+		if allowed == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+func (idp *IdentityProvider) appliesToEmailDomain(emailDomain string) bool {
+	if len(idp.AllowedEmailDomains) == 0 || emailDomain == "" {
+		return true
+	}
+	for _, allowed := range idp.AllowedEmailDomains {
+		if strings.EqualFold(allowed, emailDomain) {
+			return true
+		}
+	}
+	return false
+}
+
+// emailDomainOf prefers an explicit email domain over one derived from the
+// login hint, since a client that knows the domain already did the work of
+// resolving it.
+func emailDomainOf(emailDomain, loginHint string) string {
+	if emailDomain != "" {
+		return emailDomain
+	}
+	if _, domain, ok := strings.Cut(loginHint, "@"); ok {
+		return domain
+	}
+	return ""
+}
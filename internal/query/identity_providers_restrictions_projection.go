@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+// StartIDPRestrictionsProjection subscribes to instance.IDPRestrictionsSetEventType
+// and keeps projections.idp_restrictions in sync, so GetActiveIdentityProviders'
+// restriction filtering and priority ranking observe a SetIDPRestrictions write
+// as soon as it is pushed.
+func (q *Queries) StartIDPRestrictionsProjection(ctx context.Context, es *eventstore.Eventstore) {
+	sub := eventstore.SubscribeEventTypes(instance.IDPRestrictionsSetEventType)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt := <-sub.Events:
+				event, ok := evt.(*instance.IDPRestrictionsSetEvent)
+				if !ok {
+					continue
+				}
+				q.reduceIDPRestrictionsSet(ctx, event)
+			}
+		}
+	}()
+}
+
+func (q *Queries) reduceIDPRestrictionsSet(ctx context.Context, event *instance.IDPRestrictionsSetEvent) {
+	stmt, args, err := sq.Insert(idpRestrictionsTable.name).
+		Columns(
+			idpRestrictionsIDPIDCol.name,
+			idpRestrictionsAllowedEmailDomainsCol.name,
+			idpRestrictionsAllowedClientIDsCol.name,
+			idpRestrictionsPriorityCol.name,
+		).
+		Values(
+			event.IDPID,
+			pq.Array(event.AllowedEmailDomains),
+			pq.Array(event.AllowedClientIDs),
+			event.Priority,
+		).
+		Suffix("ON CONFLICT (" + idpRestrictionsIDPIDCol.name + ") DO UPDATE SET " +
+			idpRestrictionsAllowedEmailDomainsCol.name + " = EXCLUDED." + idpRestrictionsAllowedEmailDomainsCol.name + ", " +
+			idpRestrictionsAllowedClientIDsCol.name + " = EXCLUDED." + idpRestrictionsAllowedClientIDsCol.name + ", " +
+			idpRestrictionsPriorityCol.name + " = EXCLUDED." + idpRestrictionsPriorityCol.name).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return
+	}
+	// Best-effort: a projection write failure must never block the
+	// subscription loop from processing the next event.
+	_, _ = q.client.ExecContext(ctx, stmt, args...)
+}
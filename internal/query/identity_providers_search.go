@@ -0,0 +1,153 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+var (
+	loginPolicyIDPsTable = table{
+		name: "projections.idp_login_policy_links",
+	}
+	loginPolicyIDPIDCol = Column{
+		name:  "idp_id",
+		table: loginPolicyIDPsTable,
+	}
+	idpTemplateTable = table{
+		name: "projections.idp_templates",
+	}
+	idpTemplateIDCol = Column{
+		name:  "id",
+		table: idpTemplateTable,
+	}
+	idpTemplateNameCol = Column{
+		name:  "name",
+		table: idpTemplateTable,
+	}
+	idpTemplateTypeCol = Column{
+		name:  "type",
+		table: idpTemplateTable,
+	}
+	// idpRestrictionsTable is kept in sync with instance.IDPRestrictionsSetEvent
+	// by StartIDPRestrictionsProjection (identity_providers_restrictions_projection.go).
+	idpRestrictionsTable = table{
+		name: "projections.idp_restrictions",
+	}
+	idpRestrictionsIDPIDCol = Column{
+		name:  "idp_id",
+		table: idpRestrictionsTable,
+	}
+	idpRestrictionsAllowedEmailDomainsCol = Column{
+		name:  "allowed_email_domains",
+		table: idpRestrictionsTable,
+	}
+	idpRestrictionsAllowedClientIDsCol = Column{
+		name:  "allowed_client_ids",
+		table: idpRestrictionsTable,
+	}
+	idpRestrictionsPriorityCol = Column{
+		name:  "priority",
+		table: idpRestrictionsTable,
+	}
+	loginPolicyIDPInstanceIDCol = Column{
+		name:  "instance_id",
+		table: loginPolicyIDPsTable,
+	}
+	loginPolicyIDPLinkingAllowedCol = Column{
+		name:  "linking_allowed",
+		table: loginPolicyIDPsTable,
+	}
+	loginPolicyIDPCreationAllowedCol = Column{
+		name:  "creation_allowed",
+		table: loginPolicyIDPsTable,
+	}
+	loginPolicyIDPAutoCreationCol = Column{
+		name:  "auto_creation",
+		table: loginPolicyIDPsTable,
+	}
+	loginPolicyIDPAutoLinkingCol = Column{
+		name:  "auto_linking",
+		table: loginPolicyIDPsTable,
+	}
+	loginPolicyIDPSequenceCol = Column{
+		name:  "sequence",
+		table: loginPolicyIDPsTable,
+	}
+)
+
+// searchActiveIdentityProviders joins the login policy's active identity
+// providers with their optional restrictions, applying the login policy
+// flag filters (linking/creation/auto-X allowed) in the WHERE clause. The
+// client/email restriction filtering and priority ranking is applied by the
+// caller since it depends on the login attempt, not on stored state alone;
+// rows are ordered by the sequence the provider was added to the login
+// policy so that ranking, which only reorders by priority, is stable on
+// actual insertion order rather than whatever order Postgres happens to
+// return.
+func (q *Queries) searchActiveIdentityProviders(ctx context.Context, queries *ActiveIdentityProvidersSearchQueries) ([]*IdentityProvider, error) {
+	builder := sq.Select(
+		idpTemplateIDCol.identifier(),
+		idpTemplateNameCol.identifier(),
+		idpTemplateTypeCol.identifier(),
+		idpRestrictionsAllowedEmailDomainsCol.identifier(),
+		idpRestrictionsAllowedClientIDsCol.identifier(),
+		idpRestrictionsPriorityCol.identifier(),
+	).From(loginPolicyIDPsTable.identifier()).
+		Join(idpTemplateTable.identifier() + " ON " + idpTemplateIDCol.identifier() + " = " + loginPolicyIDPIDCol.identifier()).
+		LeftJoin(idpRestrictionsTable.identifier() + " ON " + idpRestrictionsIDPIDCol.identifier() + " = " + idpTemplateIDCol.identifier()).
+		Where(sq.Eq{loginPolicyIDPInstanceIDCol.identifier(): authz.GetInstance(ctx).InstanceID()}).
+		OrderBy(loginPolicyIDPSequenceCol.identifier()).
+		PlaceholderFormat(sq.Dollar)
+
+	if queries.LinkingAllowed != nil {
+		builder = builder.Where(sq.Eq{loginPolicyIDPLinkingAllowedCol.identifier(): *queries.LinkingAllowed})
+	}
+	if queries.CreationAllowed != nil {
+		builder = builder.Where(sq.Eq{loginPolicyIDPCreationAllowedCol.identifier(): *queries.CreationAllowed})
+	}
+	if queries.AutoCreation != nil {
+		builder = builder.Where(sq.Eq{loginPolicyIDPAutoCreationCol.identifier(): *queries.AutoCreation})
+	}
+	if queries.AutoLinking != nil {
+		builder = builder.Where(sq.Eq{loginPolicyIDPAutoLinkingCol.identifier(): *queries.AutoLinking})
+	}
+
+	stmt, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var idps []*IdentityProvider
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			idp := new(IdentityProvider)
+			var idpType domain.IDPType
+			// priority is NULL for providers without a restrictions row.
+			var priority sql.NullInt32
+			if err := rows.Scan(
+				&idp.ID,
+				&idp.Name,
+				&idpType,
+				pq.Array(&idp.AllowedEmailDomains),
+				pq.Array(&idp.AllowedClientIDs),
+				&priority,
+			); err != nil {
+				return err
+			}
+			idp.Type = idpType
+			idp.Priority = priority.Int32
+			idps = append(idps, idp)
+		}
+		return rows.Err()
+	}, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return idps, nil
+}
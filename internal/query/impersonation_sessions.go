@@ -0,0 +1,100 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// projections.impersonation_sessions is kept in sync with
+// instance.ImpersonationSessionStartedEvent/ImpersonationSessionEndedEvent
+// by StartImpersonationSessionsProjection (impersonation_sessions_projection.go).
+var (
+	impersonationSessionsTable = table{
+		name: "projections.impersonation_sessions",
+	}
+	ImpersonationSessionIDCol = Column{
+		name:  "id",
+		table: impersonationSessionsTable,
+	}
+	ImpersonationSessionInstanceIDCol = Column{
+		name:  "instance_id",
+		table: impersonationSessionsTable,
+	}
+	ImpersonationSessionImpersonatorUserIDCol = Column{
+		name:  "impersonator_user_id",
+		table: impersonationSessionsTable,
+	}
+	ImpersonationSessionTargetUserIDCol = Column{
+		name:  "target_user_id",
+		table: impersonationSessionsTable,
+	}
+	ImpersonationSessionJustificationCol = Column{
+		name:  "justification",
+		table: impersonationSessionsTable,
+	}
+	ImpersonationSessionStartedAtCol = Column{
+		name:  "started_at",
+		table: impersonationSessionsTable,
+	}
+	ImpersonationSessionEndedAtCol = Column{
+		name:  "ended_at",
+		table: impersonationSessionsTable,
+	}
+)
+
+// ImpersonationSession is the read model of a single impersonation session,
+// projected from the events written by Commands.StartImpersonationSession
+// and Commands.EndImpersonationSession.
+type ImpersonationSession struct {
+	ID                 string
+	ImpersonatorUserID string
+	TargetUserID       string
+	Justification      string
+	StartedAt          time.Time
+}
+
+// ActiveImpersonationSessions returns the impersonation sessions of the
+// given instance that have not been ended yet, ordered by start time.
+func (q *Queries) ActiveImpersonationSessions(ctx context.Context, instanceID string) ([]*ImpersonationSession, error) {
+	stmt, args, err := sq.Select(
+		ImpersonationSessionIDCol.identifier(),
+		ImpersonationSessionImpersonatorUserIDCol.identifier(),
+		ImpersonationSessionTargetUserIDCol.identifier(),
+		ImpersonationSessionJustificationCol.identifier(),
+		ImpersonationSessionStartedAtCol.identifier(),
+	).From(impersonationSessionsTable.identifier()).
+		Where(sq.And{
+			sq.Eq{ImpersonationSessionInstanceIDCol.identifier(): instanceID},
+			sq.Eq{ImpersonationSessionEndedAtCol.identifier(): nil},
+		}).
+		OrderBy(ImpersonationSessionStartedAtCol.identifier()).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var sessions []*ImpersonationSession
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			session := new(ImpersonationSession)
+			if err := rows.Scan(
+				&session.ID,
+				&session.ImpersonatorUserID,
+				&session.TargetUserID,
+				&session.Justification,
+				&session.StartedAt,
+			); err != nil {
+				return err
+			}
+			sessions = append(sessions, session)
+		}
+		return rows.Err()
+	}, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
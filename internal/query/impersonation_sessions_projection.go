@@ -0,0 +1,82 @@
+package query
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+// StartImpersonationSessionsProjection subscribes to
+// ImpersonationSessionStartedEventType/ImpersonationSessionEndedEventType
+// and keeps projections.impersonation_sessions in sync, so
+// ListImpersonationSessions observes a Commands.StartImpersonationSession/
+// EndImpersonationSession write as soon as it is pushed.
+func (q *Queries) StartImpersonationSessionsProjection(ctx context.Context, es *eventstore.Eventstore) {
+	sub := eventstore.SubscribeEventTypes(
+		instance.ImpersonationSessionStartedEventType,
+		instance.ImpersonationSessionEndedEventType,
+	)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt := <-sub.Events:
+				switch event := evt.(type) {
+				case *instance.ImpersonationSessionStartedEvent:
+					q.reduceImpersonationSessionStarted(ctx, event)
+				case *instance.ImpersonationSessionEndedEvent:
+					q.reduceImpersonationSessionEnded(ctx, event)
+				}
+			}
+		}
+	}()
+}
+
+func (q *Queries) reduceImpersonationSessionStarted(ctx context.Context, event *instance.ImpersonationSessionStartedEvent) {
+	stmt, args, err := sq.Insert(impersonationSessionsTable.name).
+		Columns(
+			ImpersonationSessionIDCol.name,
+			ImpersonationSessionInstanceIDCol.name,
+			ImpersonationSessionImpersonatorUserIDCol.name,
+			ImpersonationSessionTargetUserIDCol.name,
+			ImpersonationSessionJustificationCol.name,
+			ImpersonationSessionStartedAtCol.name,
+		).
+		Values(
+			event.SessionID,
+			event.Aggregate().InstanceID,
+			event.ImpersonatorUserID,
+			event.TargetUserID,
+			event.Justification,
+			event.StartedAt,
+		).
+		Suffix("ON CONFLICT (" + ImpersonationSessionIDCol.name + ") DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return
+	}
+	// Best-effort: a projection write failure must never block the
+	// subscription loop from processing the next event.
+	_, _ = q.client.ExecContext(ctx, stmt, args...)
+}
+
+func (q *Queries) reduceImpersonationSessionEnded(ctx context.Context, event *instance.ImpersonationSessionEndedEvent) {
+	stmt, args, err := sq.Update(impersonationSessionsTable.name).
+		Set(ImpersonationSessionEndedAtCol.name, event.CreatedAt()).
+		Where(sq.And{
+			sq.Eq{ImpersonationSessionIDCol.name: event.SessionID},
+			sq.Eq{ImpersonationSessionInstanceIDCol.name: event.Aggregate().InstanceID},
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return
+	}
+	_, _ = q.client.ExecContext(ctx, stmt, args...)
+}
@@ -0,0 +1,167 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+)
+
+// projections.security_policies is kept in sync with instance.SecurityPolicySetEvent
+// by StartSecurityPolicyProjection (security_policy_projection.go).
+var (
+	securityPolicyTable = table{
+		name: "projections.security_policies",
+	}
+	SecurityPolicyInstanceIDCol = Column{
+		name:  "instance_id",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyEnabledCol = Column{
+		name:  "enabled",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyAllowedOriginsCol = Column{
+		name:  "allowed_origins",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyImpersonationEnabledCol = Column{
+		name:  "impersonation_enabled",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyImpersonationAllowedRolesCol = Column{
+		name:  "impersonation_allowed_roles",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyImpersonationEligibleTargetUserTypesCol = Column{
+		name:  "impersonation_eligible_target_user_types",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyImpersonationMaxSessionDurationCol = Column{
+		name:  "impersonation_max_session_duration",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyImpersonationJustificationRequiredCol = Column{
+		name:  "impersonation_justification_required",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyImpersonationAuditSinkURLCol = Column{
+		name:  "impersonation_audit_sink_url",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyM2MTokenTrustEnabledCol = Column{
+		name:  "m2m_token_trust_enabled",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyM2MTokenTrustCacheExpirationSecondsCol = Column{
+		name:  "m2m_token_trust_cache_expiration_seconds",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyM2MTokenTrustTrustedIssuersCol = Column{
+		name:  "m2m_token_trust_trusted_issuers",
+		table: securityPolicyTable,
+	}
+	SecurityPolicyM2MTokenTrustRequiredAudiencesCol = Column{
+		name:  "m2m_token_trust_required_audiences",
+		table: securityPolicyTable,
+	}
+	SecurityPolicySequenceCol = Column{
+		name:  "sequence",
+		table: securityPolicyTable,
+	}
+)
+
+// SecurityPolicy is the read model of the instance wide security settings,
+// projected from the events written by internal/command.
+type SecurityPolicy struct {
+	InstanceID     string
+	Enabled        bool
+	AllowedOrigins []string
+
+	M2MTokenTrust       M2MTokenTrust
+	ImpersonationPolicy ImpersonationPolicy
+
+	// ResourceVersion is the ETag GetSecuritySettings returns to the client
+	// and UpdateSecuritySettings must echo back to detect concurrent edits.
+	// It mirrors the projection's sequence column.
+	ResourceVersion string
+}
+
+// M2MTokenTrust is the read model of the M2M token trust configuration
+// consulted by the auth middleware when validating machine-to-machine JWTs.
+type M2MTokenTrust struct {
+	Enabled                bool
+	CacheExpirationSeconds int32
+	TrustedIssuers         []string
+	RequiredAudiences      []string
+}
+
+// ImpersonationPolicy is the read model of the scoped impersonation
+// configuration that replaced the single EnableImpersonation flag.
+type ImpersonationPolicy struct {
+	Enabled                 bool
+	AllowedRoles            []string
+	EligibleTargetUserTypes []string
+	MaxSessionDuration      time.Duration
+	JustificationRequired   bool
+	AuditSinkURL            string
+}
+
+// SecurityPolicyByInstanceID returns the security policy of the given
+// instance, falling back to a zero value policy (everything disabled) if
+// none has been set yet.
+func (q *Queries) SecurityPolicyByInstanceID(ctx context.Context, instanceID string) (*SecurityPolicy, error) {
+	if instanceID == "" {
+		instanceID = authz.GetInstance(ctx).InstanceID()
+	}
+	stmt, args, err := sq.Select(
+		SecurityPolicyEnabledCol.identifier(),
+		SecurityPolicyAllowedOriginsCol.identifier(),
+		SecurityPolicyM2MTokenTrustEnabledCol.identifier(),
+		SecurityPolicyM2MTokenTrustCacheExpirationSecondsCol.identifier(),
+		SecurityPolicyM2MTokenTrustTrustedIssuersCol.identifier(),
+		SecurityPolicyM2MTokenTrustRequiredAudiencesCol.identifier(),
+		SecurityPolicyImpersonationEnabledCol.identifier(),
+		SecurityPolicyImpersonationAllowedRolesCol.identifier(),
+		SecurityPolicyImpersonationEligibleTargetUserTypesCol.identifier(),
+		SecurityPolicyImpersonationMaxSessionDurationCol.identifier(),
+		SecurityPolicyImpersonationJustificationRequiredCol.identifier(),
+		SecurityPolicyImpersonationAuditSinkURLCol.identifier(),
+		SecurityPolicySequenceCol.identifier(),
+	).From(securityPolicyTable.identifier()).
+		Where(sq.Eq{SecurityPolicyInstanceIDCol.identifier(): instanceID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	policy := &SecurityPolicy{InstanceID: instanceID}
+	var sequence uint64
+	err = q.client.QueryRowContext(ctx, func(row *sql.Row) error {
+		return row.Scan(
+			&policy.Enabled,
+			pq.Array(&policy.AllowedOrigins),
+			&policy.M2MTokenTrust.Enabled,
+			&policy.M2MTokenTrust.CacheExpirationSeconds,
+			pq.Array(&policy.M2MTokenTrust.TrustedIssuers),
+			pq.Array(&policy.M2MTokenTrust.RequiredAudiences),
+			&policy.ImpersonationPolicy.Enabled,
+			pq.Array(&policy.ImpersonationPolicy.AllowedRoles),
+			pq.Array(&policy.ImpersonationPolicy.EligibleTargetUserTypes),
+			&policy.ImpersonationPolicy.MaxSessionDuration,
+			&policy.ImpersonationPolicy.JustificationRequired,
+			&policy.ImpersonationPolicy.AuditSinkURL,
+			&sequence,
+		)
+	}, stmt, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	policy.ResourceVersion = strconv.FormatUint(sequence, 10)
+	return policy, nil
+}
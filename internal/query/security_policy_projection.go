@@ -0,0 +1,102 @@
+package query
+
+import (
+	"context"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+// StartSecurityPolicyProjection subscribes to instance.SecurityPolicySetEventType
+// and keeps projections.security_policies in sync, so SecurityPolicyByInstanceID
+// (and everything built on it: GetSecuritySettings, the CORS middleware, the
+// M2M token trust verifier) observes a SetSecurityPolicy/UpdateSecurityPolicy/
+// BulkSetSecurityPolicy write as soon as it is pushed.
+func (q *Queries) StartSecurityPolicyProjection(ctx context.Context, es *eventstore.Eventstore) {
+	sub := eventstore.SubscribeEventTypes(instance.SecurityPolicySetEventType)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt := <-sub.Events:
+				event, ok := evt.(*instance.SecurityPolicySetEvent)
+				if !ok {
+					continue
+				}
+				q.reduceSecurityPolicySet(ctx, event)
+			}
+		}
+	}()
+}
+
+// reduceSecurityPolicySet upserts the fields that are non-nil on event,
+// leaving the stored value of every other column untouched - the same
+// partial-update semantics SetSecurityPolicy/UpdateSecurityPolicy apply on
+// the write side.
+func (q *Queries) reduceSecurityPolicySet(ctx context.Context, event *instance.SecurityPolicySetEvent) {
+	cols := []string{SecurityPolicyInstanceIDCol.name, SecurityPolicySequenceCol.name}
+	vals := []interface{}{event.Aggregate().InstanceID, event.Sequence()}
+	updates := []string{SecurityPolicySequenceCol.name + " = EXCLUDED." + SecurityPolicySequenceCol.name}
+
+	set := func(col Column, val interface{}) {
+		cols = append(cols, col.name)
+		vals = append(vals, val)
+		updates = append(updates, col.name+" = EXCLUDED."+col.name)
+	}
+
+	if event.Enabled != nil {
+		set(SecurityPolicyEnabledCol, *event.Enabled)
+	}
+	if event.AllowedOrigins != nil {
+		set(SecurityPolicyAllowedOriginsCol, pq.Array(*event.AllowedOrigins))
+	}
+	if event.M2MTokenTrustEnabled != nil {
+		set(SecurityPolicyM2MTokenTrustEnabledCol, *event.M2MTokenTrustEnabled)
+	}
+	if event.M2MTokenTrustCacheExpirationSeconds != nil {
+		set(SecurityPolicyM2MTokenTrustCacheExpirationSecondsCol, *event.M2MTokenTrustCacheExpirationSeconds)
+	}
+	if event.M2MTokenTrustTrustedIssuers != nil {
+		set(SecurityPolicyM2MTokenTrustTrustedIssuersCol, pq.Array(*event.M2MTokenTrustTrustedIssuers))
+	}
+	if event.M2MTokenTrustRequiredAudiences != nil {
+		set(SecurityPolicyM2MTokenTrustRequiredAudiencesCol, pq.Array(*event.M2MTokenTrustRequiredAudiences))
+	}
+	if event.ImpersonationEnabled != nil {
+		set(SecurityPolicyImpersonationEnabledCol, *event.ImpersonationEnabled)
+	}
+	if event.ImpersonationAllowedRoles != nil {
+		set(SecurityPolicyImpersonationAllowedRolesCol, pq.Array(*event.ImpersonationAllowedRoles))
+	}
+	if event.ImpersonationEligibleTargetUserTypes != nil {
+		set(SecurityPolicyImpersonationEligibleTargetUserTypesCol, pq.Array(*event.ImpersonationEligibleTargetUserTypes))
+	}
+	if event.ImpersonationMaxSessionDuration != nil {
+		set(SecurityPolicyImpersonationMaxSessionDurationCol, *event.ImpersonationMaxSessionDuration)
+	}
+	if event.ImpersonationJustificationRequired != nil {
+		set(SecurityPolicyImpersonationJustificationRequiredCol, *event.ImpersonationJustificationRequired)
+	}
+	if event.ImpersonationAuditSinkURL != nil {
+		set(SecurityPolicyImpersonationAuditSinkURLCol, *event.ImpersonationAuditSinkURL)
+	}
+
+	stmt, args, err := sq.Insert(securityPolicyTable.name).
+		Columns(cols...).
+		Values(vals...).
+		Suffix("ON CONFLICT (" + SecurityPolicyInstanceIDCol.name + ") DO UPDATE SET " + strings.Join(updates, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return
+	}
+	// Best-effort: a projection write failure must never block the
+	// subscription loop from processing the next event.
+	_, _ = q.client.ExecContext(ctx, stmt, args...)
+}
@@ -0,0 +1,56 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	IDPRestrictionsSetEventType = instanceEventTypePrefix + "idp.restrictions.set"
+)
+
+type IDPRestrictionsSetEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	IDPID               string   `json:"idpId"`
+	AllowedEmailDomains []string `json:"allowedEmailDomains,omitempty"`
+	AllowedClientIDs    []string `json:"allowedClientIds,omitempty"`
+	Priority            int32    `json:"priority,omitempty"`
+}
+
+func NewIDPRestrictionsSetEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	idpID string,
+	allowedEmailDomains, allowedClientIDs []string,
+	priority int32,
+) *IDPRestrictionsSetEvent {
+	return &IDPRestrictionsSetEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			IDPRestrictionsSetEventType,
+		),
+		IDPID:               idpID,
+		AllowedEmailDomains: allowedEmailDomains,
+		AllowedClientIDs:    allowedClientIDs,
+		Priority:            priority,
+	}
+}
+
+func (e *IDPRestrictionsSetEvent) Payload() interface{} {
+	return e
+}
+
+func (e *IDPRestrictionsSetEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func IDPRestrictionsSetEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e := &IDPRestrictionsSetEvent{BaseEvent: *eventstore.BaseEventFromRepo(event)}
+	if err := event.Unmarshal(e); err != nil {
+		return nil, eventstore.ThrowInternal(err, "INSTA-oo1Vr", "unable to unmarshal idp restrictions set event")
+	}
+	return e, nil
+}
@@ -0,0 +1,84 @@
+package instance
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	ImpersonationSessionStartedEventType = instanceEventTypePrefix + "impersonation.session.started"
+	ImpersonationSessionEndedEventType   = instanceEventTypePrefix + "impersonation.session.ended"
+)
+
+type ImpersonationSessionStartedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	SessionID          string    `json:"sessionId"`
+	ImpersonatorUserID string    `json:"impersonatorUserId"`
+	TargetUserID       string    `json:"targetUserId"`
+	Justification      string    `json:"justification,omitempty"`
+	StartedAt          time.Time `json:"startedAt"`
+}
+
+func NewImpersonationSessionStartedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	sessionID, impersonatorUserID, targetUserID, justification string,
+	startedAt time.Time,
+) *ImpersonationSessionStartedEvent {
+	return &ImpersonationSessionStartedEvent{
+		BaseEvent:          *eventstore.NewBaseEventForPush(ctx, aggregate, ImpersonationSessionStartedEventType),
+		SessionID:          sessionID,
+		ImpersonatorUserID: impersonatorUserID,
+		TargetUserID:       targetUserID,
+		Justification:      justification,
+		StartedAt:          startedAt,
+	}
+}
+
+func (e *ImpersonationSessionStartedEvent) Payload() interface{} { return e }
+
+func (e *ImpersonationSessionStartedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func ImpersonationSessionStartedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e := &ImpersonationSessionStartedEvent{BaseEvent: *eventstore.BaseEventFromRepo(event)}
+	if err := event.Unmarshal(e); err != nil {
+		return nil, eventstore.ThrowInternal(err, "INSTA-ee3Ax", "unable to unmarshal impersonation session started event")
+	}
+	return e, nil
+}
+
+type ImpersonationSessionEndedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	SessionID string `json:"sessionId"`
+}
+
+func NewImpersonationSessionEndedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	sessionID string,
+) *ImpersonationSessionEndedEvent {
+	return &ImpersonationSessionEndedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(ctx, aggregate, ImpersonationSessionEndedEventType),
+		SessionID: sessionID,
+	}
+}
+
+func (e *ImpersonationSessionEndedEvent) Payload() interface{} { return e }
+
+func (e *ImpersonationSessionEndedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func ImpersonationSessionEndedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e := &ImpersonationSessionEndedEvent{BaseEvent: *eventstore.BaseEventFromRepo(event)}
+	if err := event.Unmarshal(e); err != nil {
+		return nil, eventstore.ThrowInternal(err, "INSTA-Oph1a", "unable to unmarshal impersonation session ended event")
+	}
+	return e, nil
+}
@@ -0,0 +1,41 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/policy"
+)
+
+const (
+	SecurityPolicySetEventType = instanceEventTypePrefix + policy.SecurityPolicySetEventType
+)
+
+type SecurityPolicySetEvent struct {
+	policy.SecurityPolicySetEvent
+}
+
+func NewSecurityPolicySetEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	changes []policy.SecurityPolicyChanges,
+) *SecurityPolicySetEvent {
+	return &SecurityPolicySetEvent{
+		SecurityPolicySetEvent: *policy.NewSecurityPolicySetEvent(
+			eventstore.NewBaseEventForPush(
+				ctx,
+				aggregate,
+				SecurityPolicySetEventType,
+			),
+			changes,
+		),
+	}
+}
+
+func SecurityPolicySetEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e, err := policy.SecurityPolicySetEventMapper(event)
+	if err != nil {
+		return nil, err
+	}
+	return &SecurityPolicySetEvent{SecurityPolicySetEvent: *e.(*policy.SecurityPolicySetEvent)}, nil
+}